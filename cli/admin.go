@@ -0,0 +1,246 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	cli "github.com/pressly/cli"
+	"ytsruh.com/envoy/cli/controllers"
+	"ytsruh.com/envoy/cli/prompts"
+	shared "ytsruh.com/envoy/shared"
+)
+
+var adminCmd = &cli.Command{
+	Name:      "admin",
+	ShortHelp: "Instance administration (admins only)",
+	SubCommands: []*cli.Command{
+		adminUsersCmd,
+		adminProjectsCmd,
+		adminDisableCmd,
+		adminEnableCmd,
+		adminTransferCmd,
+		adminStatsCmd,
+	},
+}
+
+// requireAdminClient returns an authenticated client, exiting with the usual
+// login hints when no valid token is available.
+func requireAdminClient(s *cli.State) *controllers.Client {
+	client, err := controllers.RequireToken()
+	if err != nil {
+		fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+		if err == shared.ErrNoToken {
+			fmt.Fprintln(s.Stdout, "Please login first using 'envoy login'")
+		}
+		os.Exit(1)
+	}
+	return client
+}
+
+// findUserByEmail looks up a user by exact email using the admin user list.
+func findUserByEmail(client *controllers.Client, email string) (*shared.AdminUserResponse, error) {
+	users, err := client.AdminListUsers()
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		if strings.EqualFold(u.Email, email) {
+			return &u, nil
+		}
+	}
+	return nil, fmt.Errorf("no user found with email '%s'", email)
+}
+
+var adminUsersCmd = &cli.Command{
+	Name:      "users",
+	ShortHelp: "List all users",
+	Usage:     "envoy admin users [flags]",
+	Exec: func(ctx context.Context, s *cli.State) error {
+		client := requireAdminClient(s)
+
+		users, err := client.AdminListUsers()
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to list users: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(users) == 0 {
+			fmt.Fprintln(s.Stdout, "No users found.")
+			return nil
+		}
+
+		fmt.Fprintf(s.Stdout, "Found %d user(s):\n\n", len(users))
+		for _, u := range users {
+			fmt.Fprintf(s.Stdout, "  Name:  %s\n", u.Name)
+			fmt.Fprintf(s.Stdout, "  Email: %s\n", u.Email)
+			fmt.Fprintf(s.Stdout, "  ID:    %s\n", u.UserID)
+			if u.IsAdmin {
+				fmt.Fprintln(s.Stdout, "  Admin: yes")
+			}
+			if u.DisabledAt != nil {
+				fmt.Fprintf(s.Stdout, "  Disabled: %s\n", u.DisabledAt.ToTime().Format("2006-01-02 15:04"))
+			}
+			fmt.Fprintln(s.Stdout)
+		}
+		return nil
+	},
+}
+
+var adminProjectsCmd = &cli.Command{
+	Name:      "projects",
+	ShortHelp: "List all projects and their owners",
+	Usage:     "envoy admin projects [flags]",
+	Exec: func(ctx context.Context, s *cli.State) error {
+		client := requireAdminClient(s)
+
+		projects, err := client.AdminListProjects()
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to list projects: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(projects) == 0 {
+			fmt.Fprintln(s.Stdout, "No projects found.")
+			return nil
+		}
+
+		fmt.Fprintf(s.Stdout, "Found %d project(s):\n\n", len(projects))
+		for _, p := range projects {
+			fmt.Fprintf(s.Stdout, "  Name:  %s\n", p.Name)
+			fmt.Fprintf(s.Stdout, "  ID:    %s\n", p.ID)
+			if p.OwnerDisabled {
+				fmt.Fprintf(s.Stdout, "  Owner: %s (disabled)\n", p.OwnerEmail)
+			} else {
+				fmt.Fprintf(s.Stdout, "  Owner: %s\n", p.OwnerEmail)
+			}
+			if p.GitRepo != nil && *p.GitRepo != "" {
+				fmt.Fprintf(s.Stdout, "  Git Repository: %s\n", *p.GitRepo)
+			}
+			fmt.Fprintln(s.Stdout)
+		}
+		return nil
+	},
+}
+
+var adminDisableCmd = &cli.Command{
+	Name:      "disable",
+	ShortHelp: "Disable a user account",
+	Usage:     "envoy admin disable [email] [flags]",
+	Exec: func(ctx context.Context, s *cli.State) error {
+		return setUserDisabledExec(s, true)
+	},
+}
+
+var adminEnableCmd = &cli.Command{
+	Name:      "enable",
+	ShortHelp: "Re-enable a disabled user account",
+	Usage:     "envoy admin enable [email] [flags]",
+	Exec: func(ctx context.Context, s *cli.State) error {
+		return setUserDisabledExec(s, false)
+	},
+}
+
+func setUserDisabledExec(s *cli.State, disable bool) error {
+	client := requireAdminClient(s)
+
+	var email string
+	var err error
+	if len(s.Args) == 1 {
+		email = s.Args[0]
+	} else {
+		email, err = prompts.PromptString("User email", true)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	user, err := findUserByEmail(client, email)
+	if err != nil {
+		fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if disable {
+		_, err = client.AdminDisableUser(string(user.UserID))
+	} else {
+		_, err = client.AdminEnableUser(string(user.UserID))
+	}
+	if err != nil {
+		fmt.Fprintf(s.Stderr, "Failed to update user: %v\n", err)
+		os.Exit(1)
+	}
+
+	if disable {
+		fmt.Fprintf(s.Stdout, "Disabled %s (%s)\n", user.Name, user.Email)
+	} else {
+		fmt.Fprintf(s.Stdout, "Enabled %s (%s)\n", user.Name, user.Email)
+	}
+	return nil
+}
+
+var adminTransferCmd = &cli.Command{
+	Name:      "transfer",
+	ShortHelp: "Transfer ownership of a project to another user",
+	Usage:     "envoy admin transfer [project-id] [email] [flags]",
+	Exec: func(ctx context.Context, s *cli.State) error {
+		client := requireAdminClient(s)
+
+		var projectID, email string
+		var err error
+		if len(s.Args) == 2 {
+			projectID = s.Args[0]
+			email = s.Args[1]
+		} else {
+			projectID, err = prompts.PromptString("Project ID", true)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			email, err = prompts.PromptString("New owner email", true)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		user, err := findUserByEmail(client, email)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		project, err := client.AdminTransferProject(projectID, string(user.UserID))
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to transfer project: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Fprintf(s.Stdout, "Project '%s' is now owned by %s\n", project.Name, project.OwnerEmail)
+		return nil
+	},
+}
+
+var adminStatsCmd = &cli.Command{
+	Name:      "stats",
+	ShortHelp: "Show instance-wide statistics",
+	Usage:     "envoy admin stats [flags]",
+	Exec: func(ctx context.Context, s *cli.State) error {
+		client := requireAdminClient(s)
+
+		stats, err := client.AdminGetStats()
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to get stats: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Fprintln(s.Stdout, "Instance Statistics:")
+		fmt.Fprintf(s.Stdout, "  Users: %d (%d disabled)\n", stats.Users, stats.DisabledUsers)
+		fmt.Fprintf(s.Stdout, "  Projects: %d\n", stats.Projects)
+		fmt.Fprintf(s.Stdout, "  Environments: %d\n", stats.Environments)
+		fmt.Fprintf(s.Stdout, "  Variables: %d\n", stats.Variables)
+		return nil
+	},
+}
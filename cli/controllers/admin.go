@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"fmt"
+
+	shared "ytsruh.com/envoy/shared"
+)
+
+type AdminController struct {
+	*BaseClient
+}
+
+func NewAdminController(base *BaseClient) *AdminController {
+	return &AdminController{BaseClient: base}
+}
+
+func (a *AdminController) AdminListUsers() ([]shared.AdminUserResponse, error) {
+	resp, err := a.doRequest("GET", "/admin/users", nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []shared.AdminUserResponse
+	if err := a.decodeResponse(resp, &users); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (a *AdminController) AdminListProjects() ([]shared.AdminProjectResponse, error) {
+	resp, err := a.doRequest("GET", "/admin/projects", nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []shared.AdminProjectResponse
+	if err := a.decodeResponse(resp, &projects); err != nil {
+		return nil, err
+	}
+
+	return projects, nil
+}
+
+func (a *AdminController) AdminDisableUser(userID string) (*shared.AdminUserResponse, error) {
+	return a.setUserDisabled(userID, "disable")
+}
+
+func (a *AdminController) AdminEnableUser(userID string) (*shared.AdminUserResponse, error) {
+	return a.setUserDisabled(userID, "enable")
+}
+
+func (a *AdminController) setUserDisabled(userID, action string) (*shared.AdminUserResponse, error) {
+	resp, err := a.doRequest("POST", fmt.Sprintf("/admin/users/%s/%s", userID, action), nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var user shared.AdminUserResponse
+	if err := a.decodeResponse(resp, &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (a *AdminController) AdminTransferProject(projectID, userID string) (*shared.AdminProjectResponse, error) {
+	reqBody := map[string]string{
+		"user_id": userID,
+	}
+
+	resp, err := a.doRequest("POST", fmt.Sprintf("/admin/projects/%s/transfer", projectID), reqBody, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var project shared.AdminProjectResponse
+	if err := a.decodeResponse(resp, &project); err != nil {
+		return nil, err
+	}
+
+	return &project, nil
+}
+
+func (a *AdminController) AdminGetStats() (*shared.InstanceStatsResponse, error) {
+	resp, err := a.doRequest("GET", "/admin/stats", nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats shared.InstanceStatsResponse
+	if err := a.decodeResponse(resp, &stats); err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
@@ -11,6 +11,7 @@ type Client struct {
 	*ProjectsController
 	*EnvironmentsController
 	*VariablesController
+	*AdminController
 }
 
 func NewClient() (*Client, error) {
@@ -32,6 +33,7 @@ func NewClient() (*Client, error) {
 		ProjectsController:     NewProjectsController(base),
 		EnvironmentsController: NewEnvironmentsController(base),
 		VariablesController:    NewVariablesController(base),
+		AdminController:        NewAdminController(base),
 	}, nil
 }
 
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 
 	shared "ytsruh.com/envoy/shared"
 )
@@ -21,11 +22,46 @@ type EnvironmentResponse struct {
 	ProjectID   shared.ProjectID     `json:"project_id"`
 	Name        string               `json:"name"`
 	Description *string              `json:"description"`
+	ParentID    *string              `json:"parent_id"`
 	CreatedAt   shared.Timestamp     `json:"created_at"`
 	UpdatedAt   shared.Timestamp     `json:"updated_at"`
 }
 
-func (e *EnvironmentsController) CreateEnvironment(projectID string, name, description string) (*EnvironmentResponse, error) {
+type EnvironmentRef struct {
+	ID   shared.EnvironmentID `json:"id"`
+	Name string               `json:"name"`
+}
+
+type CompareVariable struct {
+	Key           string          `json:"key"`
+	Value         string          `json:"value"`
+	Origin        string          `json:"origin"`
+	InheritedFrom *EnvironmentRef `json:"inherited_from,omitempty"`
+}
+
+type ChangedVariable struct {
+	Key                 string          `json:"key"`
+	BaseValue           string          `json:"base_value"`
+	TargetValue         string          `json:"target_value"`
+	BaseOrigin          string          `json:"base_origin"`
+	BaseInheritedFrom   *EnvironmentRef `json:"base_inherited_from,omitempty"`
+	TargetOrigin        string          `json:"target_origin"`
+	TargetInheritedFrom *EnvironmentRef `json:"target_inherited_from,omitempty"`
+}
+
+type CompareEnvironmentsResponse struct {
+	Base         EnvironmentRef    `json:"base"`
+	Target       EnvironmentRef    `json:"target"`
+	OnlyInBase   []CompareVariable `json:"only_in_base"`
+	OnlyInTarget []CompareVariable `json:"only_in_target"`
+	Changed      []ChangedVariable `json:"changed"`
+	Unchanged    int               `json:"unchanged"`
+	Masked       bool              `json:"masked"`
+}
+
+// CreateEnvironment creates an environment. parent is the ID or name of an
+// environment to inherit variables from, or empty for none.
+func (e *EnvironmentsController) CreateEnvironment(projectID string, name, description, parent string) (*EnvironmentResponse, error) {
 	reqBody := map[string]any{
 		"name": name,
 	}
@@ -34,6 +70,9 @@ func (e *EnvironmentsController) CreateEnvironment(projectID string, name, descr
 	} else {
 		reqBody["description"] = nil
 	}
+	if parent != "" {
+		reqBody["parent"] = parent
+	}
 
 	resp, err := e.doRequest("POST", fmt.Sprintf("/projects/%s/environments", projectID), reqBody, true)
 	if err != nil {
@@ -76,7 +115,9 @@ func (e *EnvironmentsController) GetEnvironment(projectID, environmentID string)
 	return &envResp, nil
 }
 
-func (e *EnvironmentsController) UpdateEnvironment(projectID, environmentID string, name, description string) (*EnvironmentResponse, error) {
+// UpdateEnvironment updates an environment. A nil parent keeps the current
+// parent and an empty one removes it.
+func (e *EnvironmentsController) UpdateEnvironment(projectID, environmentID string, name, description string, parent *string) (*EnvironmentResponse, error) {
 	reqBody := map[string]any{
 		"name": name,
 	}
@@ -85,6 +126,9 @@ func (e *EnvironmentsController) UpdateEnvironment(projectID, environmentID stri
 	} else {
 		reqBody["description"] = nil
 	}
+	if parent != nil {
+		reqBody["parent"] = *parent
+	}
 
 	resp, err := e.doRequest("PUT", fmt.Sprintf("/projects/%s/environments/%s", projectID, environmentID), reqBody, true)
 	if err != nil {
@@ -118,3 +162,99 @@ func (e *EnvironmentsController) DeleteEnvironment(projectID, environmentID stri
 
 	return nil
 }
+
+// CompareEnvironments reports the differences between two environments, given by
+// ID or name. Values are only included when reveal is set, which is audited.
+func (e *EnvironmentsController) CompareEnvironments(projectID, base, target string, reveal bool) (*CompareEnvironmentsResponse, error) {
+	query := url.Values{}
+	query.Set("base", base)
+	query.Set("target", target)
+	if reveal {
+		query.Set("reveal", "true")
+	}
+
+	resp, err := e.doRequest("GET", fmt.Sprintf("/projects/%s/environments/compare?%s", projectID, query.Encode()), nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var compareResp CompareEnvironmentsResponse
+	if err := e.decodeResponse(resp, &compareResp); err != nil {
+		return nil, err
+	}
+
+	return &compareResp, nil
+}
+
+type PromoteResult struct {
+	Key    string `json:"key"`
+	Action string `json:"action"`
+}
+
+type PromoteEnvironmentResponse struct {
+	Source      EnvironmentRef  `json:"source"`
+	Target      EnvironmentRef  `json:"target"`
+	Policy      string          `json:"policy"`
+	DryRun      bool            `json:"dry_run"`
+	Results     []PromoteResult `json:"results"`
+	Created     int             `json:"created"`
+	Overwritten int             `json:"overwritten"`
+	Skipped     int             `json:"skipped"`
+	Unchanged   int             `json:"unchanged"`
+	Conflicts   int             `json:"conflicts"`
+}
+
+// PromoteEnvironment copies keys (all when empty) from source to target using the
+// given conflict policy. With dryRun the server only reports what would happen.
+func (e *EnvironmentsController) PromoteEnvironment(projectID, source, target string, keys []string, policy string, dryRun bool) (*PromoteEnvironmentResponse, error) {
+	reqBody := map[string]any{
+		"source":  source,
+		"target":  target,
+		"keys":    keys,
+		"policy":  policy,
+		"dry_run": dryRun,
+	}
+
+	resp, err := e.doRequest("POST", fmt.Sprintf("/projects/%s/environments/promote", projectID), reqBody, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var promoteResp PromoteEnvironmentResponse
+	if err := e.decodeResponse(resp, &promoteResp); err != nil {
+		return nil, err
+	}
+
+	return &promoteResp, nil
+}
+
+type CloneEnvironmentResponse struct {
+	Environment     EnvironmentResponse `json:"environment"`
+	VariablesCopied int                 `json:"variables_copied"`
+	SkippedKeys     []string            `json:"skipped_keys"`
+}
+
+// CloneEnvironment copies an environment and its variables into a new environment
+// named name, in targetProjectID when set. With blankValues only keys are copied.
+func (e *EnvironmentsController) CloneEnvironment(projectID, environmentID string, name, description, targetProjectID string, blankValues bool) (*CloneEnvironmentResponse, error) {
+	reqBody := map[string]any{
+		"name":         name,
+		"description":  description,
+		"blank_values": blankValues,
+	}
+	if targetProjectID != "" {
+		reqBody["target_project_id"] = targetProjectID
+	}
+
+	resp, err := e.doRequest("POST", fmt.Sprintf("/projects/%s/environments/%s/clone", projectID, environmentID), reqBody, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var cloneResp CloneEnvironmentResponse
+	if err := e.decodeResponse(resp, &cloneResp); err != nil {
+		return nil, err
+	}
+
+	return &cloneResp, nil
+}
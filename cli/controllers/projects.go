@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"time"
 
 	shared "ytsruh.com/envoy/shared"
 )
@@ -131,8 +133,15 @@ func (p *ProjectsController) DeleteProject(projectID string) error {
 }
 
 type ProjectMemberResponse struct {
-	UserID string `json:"user_id"`
-	Role   string `json:"role"`
+	UserID        string     `json:"user_id"`
+	Role          string     `json:"role"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+	ElevatedUntil *time.Time `json:"elevated_until"`
+}
+
+type ElevationResponse struct {
+	Role          string    `json:"role"`
+	ElevatedUntil time.Time `json:"elevated_until"`
 }
 
 func (p *ProjectsController) ListProjectMembers(projectID string) ([]ProjectMemberResponse, error) {
@@ -149,10 +158,11 @@ func (p *ProjectsController) ListProjectMembers(projectID string) ([]ProjectMemb
 	return members, nil
 }
 
-func (p *ProjectsController) AddMember(projectID, userID, role string) error {
+func (p *ProjectsController) AddMember(projectID, userID, role, expiresIn string) error {
 	reqBody := map[string]string{
-		"user_id": userID,
-		"role":    role,
+		"user_id":    userID,
+		"role":       role,
+		"expires_in": expiresIn,
 	}
 
 	resp, err := p.doRequest("POST", fmt.Sprintf("/projects/%s/members", projectID), reqBody, true)
@@ -193,3 +203,206 @@ func (p *ProjectsController) RemoveMember(projectID, userID string) error {
 
 	return nil
 }
+
+func (p *ProjectsController) ElevateAccess(projectID, reason string) (*ElevationResponse, error) {
+	reqBody := map[string]string{
+		"reason": reason,
+	}
+
+	resp, err := p.doRequest("POST", fmt.Sprintf("/projects/%s/elevate", projectID), reqBody, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		var errResp ErrorResponse
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		if errResp.Error != "" {
+			return nil, fmt.Errorf("server error: %s", errResp.Error)
+		}
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var elevation ElevationResponse
+	if err := p.decodeResponse(resp, &elevation); err != nil {
+		return nil, err
+	}
+
+	return &elevation, nil
+}
+
+type DiscoveredProjectResponse struct {
+	ID      shared.ProjectID `json:"id"`
+	Name    string           `json:"name"`
+	GitRepo *string          `json:"git_repo"`
+}
+
+type AccessRequestResponse struct {
+	ID         string            `json:"id"`
+	ProjectID  shared.ProjectID  `json:"project_id"`
+	UserID     shared.UserID     `json:"user_id"`
+	UserName   string            `json:"user_name"`
+	UserEmail  string            `json:"user_email"`
+	Role       string            `json:"role"`
+	Message    *string           `json:"message"`
+	Status     string            `json:"status"`
+	ReviewedBy *string           `json:"reviewed_by"`
+	ReviewedAt *shared.Timestamp `json:"reviewed_at"`
+	CreatedAt  shared.Timestamp  `json:"created_at"`
+}
+
+func (p *ProjectsController) DiscoverProjects(gitRepo string) ([]DiscoveredProjectResponse, error) {
+	queryParams := url.Values{}
+	queryParams.Add("git_repo", gitRepo)
+
+	resp, err := p.doRequest("GET", "/projects/discover?"+queryParams.Encode(), nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []DiscoveredProjectResponse
+	if err := p.decodeResponse(resp, &projects); err != nil {
+		return nil, err
+	}
+
+	return projects, nil
+}
+
+func (p *ProjectsController) RequestAccess(projectID, role, message string) (*AccessRequestResponse, error) {
+	reqBody := map[string]string{
+		"project_id": projectID,
+		"role":       role,
+		"message":    message,
+	}
+
+	resp, err := p.doRequest("POST", "/access-requests", reqBody, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var accessRequest AccessRequestResponse
+	if err := p.decodeResponse(resp, &accessRequest); err != nil {
+		return nil, err
+	}
+
+	return &accessRequest, nil
+}
+
+func (p *ProjectsController) ListAccessRequests(projectID string) ([]AccessRequestResponse, error) {
+	resp, err := p.doRequest("GET", fmt.Sprintf("/projects/%s/access-requests", projectID), nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var accessRequests []AccessRequestResponse
+	if err := p.decodeResponse(resp, &accessRequests); err != nil {
+		return nil, err
+	}
+
+	return accessRequests, nil
+}
+
+func (p *ProjectsController) ApproveAccessRequest(projectID, requestID string) (*AccessRequestResponse, error) {
+	return p.reviewAccessRequest(projectID, requestID, "approve")
+}
+
+func (p *ProjectsController) DenyAccessRequest(projectID, requestID string) (*AccessRequestResponse, error) {
+	return p.reviewAccessRequest(projectID, requestID, "deny")
+}
+
+func (p *ProjectsController) reviewAccessRequest(projectID, requestID, action string) (*AccessRequestResponse, error) {
+	resp, err := p.doRequest("POST", fmt.Sprintf("/projects/%s/access-requests/%s/%s", projectID, requestID, action), nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var accessRequest AccessRequestResponse
+	if err := p.decodeResponse(resp, &accessRequest); err != nil {
+		return nil, err
+	}
+
+	return &accessRequest, nil
+}
+
+type SchemaKey struct {
+	Key          string   `json:"key"`
+	Type         string   `json:"type,omitempty"`
+	Description  string   `json:"description,omitempty"`
+	Environments []string `json:"environments,omitempty"`
+}
+
+type ProjectSchemaResponse struct {
+	Keys []SchemaKey `json:"keys"`
+}
+
+type SchemaViolation struct {
+	Key   string `json:"key"`
+	Error string `json:"error"`
+}
+
+type EnvironmentSchemaReport struct {
+	Environment EnvironmentRef    `json:"environment"`
+	Valid       bool              `json:"valid"`
+	Missing     []string          `json:"missing"`
+	Invalid     []SchemaViolation `json:"invalid"`
+}
+
+type ValidateProjectSchemaResponse struct {
+	Valid        bool                      `json:"valid"`
+	Environments []EnvironmentSchemaReport `json:"environments"`
+}
+
+func (p *ProjectsController) GetProjectSchema(projectID string) (*ProjectSchemaResponse, error) {
+	resp, err := p.doRequest("GET", fmt.Sprintf("/projects/%s/schema", projectID), nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var schemaResp ProjectSchemaResponse
+	if err := p.decodeResponse(resp, &schemaResp); err != nil {
+		return nil, err
+	}
+
+	return &schemaResp, nil
+}
+
+// UpdateProjectSchema replaces the project's schema with keys.
+func (p *ProjectsController) UpdateProjectSchema(projectID string, keys []SchemaKey) (*ProjectSchemaResponse, error) {
+	reqBody := map[string]any{
+		"keys": keys,
+	}
+
+	resp, err := p.doRequest("PUT", fmt.Sprintf("/projects/%s/schema", projectID), reqBody, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var schemaResp ProjectSchemaResponse
+	if err := p.decodeResponse(resp, &schemaResp); err != nil {
+		return nil, err
+	}
+
+	return &schemaResp, nil
+}
+
+// ValidateProjectSchema checks the project's environments against its schema.
+// An empty environment checks every environment.
+func (p *ProjectsController) ValidateProjectSchema(projectID, environment string) (*ValidateProjectSchemaResponse, error) {
+	path := fmt.Sprintf("/projects/%s/schema/validate", projectID)
+	if environment != "" {
+		path += "?environment=" + url.QueryEscape(environment)
+	}
+
+	resp, err := p.doRequest("GET", path, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var validateResp ValidateProjectSchemaResponse
+	if err := p.decodeResponse(resp, &validateResp); err != nil {
+		return nil, err
+	}
+
+	return &validateResp, nil
+}
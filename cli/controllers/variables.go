@@ -1,9 +1,15 @@
 package controllers
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	shared "ytsruh.com/envoy/shared"
 )
@@ -17,19 +23,103 @@ func NewVariablesController(base *BaseClient) *VariablesController {
 }
 
 type EnvironmentVariableResponse struct {
-	ID            shared.EnvironmentVariableID `json:"id"`
-	EnvironmentID shared.EnvironmentID         `json:"environment_id"`
-	Key           string                       `json:"key"`
-	Value         string                       `json:"value"`
-	Description   *string                      `json:"description"`
-	CreatedAt     shared.Timestamp             `json:"created_at"`
-	UpdatedAt     shared.Timestamp             `json:"updated_at"`
+	ID              shared.EnvironmentVariableID `json:"id"`
+	EnvironmentID   shared.EnvironmentID         `json:"environment_id"`
+	Key             string                       `json:"key"`
+	Value           string                       `json:"value"`
+	Description     *string                      `json:"description"`
+	Type            string                       `json:"type"`
+	AllowedValues   []string                     `json:"allowed_values"`
+	Filename        string                       `json:"filename"`
+	Mode            string                       `json:"mode"`
+	Labels          map[string]string            `json:"labels"`
+	Redacted        bool                         `json:"redacted"`
+	Masked          bool                         `json:"masked"`
+	Origin          string                       `json:"origin"`
+	InheritedFrom   *EnvironmentRef              `json:"inherited_from"`
+	ResolutionError string                       `json:"resolution_error"`
+	RotateAfter     string                       `json:"rotate_after"`
+	RotateBy        shared.Timestamp             `json:"rotate_by"`
+	RotationOverdue bool                         `json:"rotation_overdue"`
+	ExpiresAt       shared.Timestamp             `json:"expires_at"`
+	Expired         bool                         `json:"expired"`
+	CreatedAt       shared.Timestamp             `json:"created_at"`
+	UpdatedAt       shared.Timestamp             `json:"updated_at"`
 }
 
-func (v *VariablesController) CreateEnvironmentVariable(projectID, environmentID string, key, value string) (*EnvironmentVariableResponse, error) {
+// TypeLabel returns the variable type for display, listing the allowed values of enums.
+func (v EnvironmentVariableResponse) TypeLabel() string {
+	if v.Type == "" {
+		return "string"
+	}
+	if len(v.AllowedValues) > 0 {
+		return fmt.Sprintf("%s (%s)", v.Type, strings.Join(v.AllowedValues, ", "))
+	}
+	return v.Type
+}
+
+// RedactedValue is displayed in place of values hidden by metadata-only access.
+const RedactedValue = "******** (redacted)"
+
+// MaskedValue is displayed in place of values that were not explicitly revealed.
+const MaskedValue = "********"
+
+// DisplayValue returns the value for display, marking redacted and masked values clearly.
+func (v EnvironmentVariableResponse) DisplayValue() string {
+	if v.Redacted {
+		return RedactedValue
+	}
+	if v.Masked {
+		return MaskedValue
+	}
+	return v.Value
+}
+
+// VariableRotation is the rotation metadata of a variable. RotateAfter is a
+// duration such as "90d"; a zero ExpiresAt means the value does not expire.
+type VariableRotation struct {
+	RotateAfter string
+	ExpiresAt   time.Time
+}
+
+// expiresAt formats the expiry for a request body, empty when there is none.
+func (r VariableRotation) expiresAt() string {
+	if r.ExpiresAt.IsZero() {
+		return ""
+	}
+	return r.ExpiresAt.Format(time.RFC3339)
+}
+
+// CreateEnvironmentVariable creates a variable of the given type. An empty type
+// creates a string; allowedValues is only used by enums. When generate holds a
+// generator spec such as "base64:32" the server generates the value instead.
+// An empty description leaves the variable without one.
+func (v *VariablesController) CreateEnvironmentVariable(projectID, environmentID string, key, value, description, variableType string, allowedValues []string, generate string, rotation VariableRotation, labels map[string]string) (*EnvironmentVariableResponse, error) {
 	reqBody := map[string]any{
-		"key":   key,
-		"value": value,
+		"key": key,
+	}
+	if generate != "" {
+		reqBody["generate"] = generate
+	} else {
+		reqBody["value"] = value
+	}
+	if description != "" {
+		reqBody["description"] = description
+	}
+	if variableType != "" {
+		reqBody["type"] = variableType
+	}
+	if len(allowedValues) > 0 {
+		reqBody["allowed_values"] = allowedValues
+	}
+	if rotation.RotateAfter != "" {
+		reqBody["rotate_after"] = rotation.RotateAfter
+	}
+	if expiresAt := rotation.expiresAt(); expiresAt != "" {
+		reqBody["expires_at"] = expiresAt
+	}
+	if len(labels) > 0 {
+		reqBody["labels"] = labels
 	}
 
 	resp, err := v.doRequest("POST", fmt.Sprintf("/projects/%s/environments/%s/variables", projectID, environmentID), reqBody, true)
@@ -45,8 +135,100 @@ func (v *VariablesController) CreateEnvironmentVariable(projectID, environmentID
 	return &varResp, nil
 }
 
-func (v *VariablesController) ListEnvironmentVariables(projectID, environmentID string) ([]EnvironmentVariableResponse, error) {
-	resp, err := v.doRequest("GET", fmt.Sprintf("/projects/%s/environments/%s/variables", projectID, environmentID), nil, true)
+// VariableTypeFile is the type of variables holding file contents.
+const VariableTypeFile = "file"
+
+// IsFile reports whether the variable holds the contents of a file.
+func (v EnvironmentVariableResponse) IsFile() bool {
+	return v.Type == VariableTypeFile
+}
+
+// FileContents decodes the contents of a revealed file variable.
+func (v EnvironmentVariableResponse) FileContents() ([]byte, error) {
+	if v.Redacted || v.Masked {
+		return nil, fmt.Errorf("the contents of %s are hidden", v.Key)
+	}
+	data, err := base64.StdEncoding.DecodeString(v.Value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid contents for %s: %w", v.Key, err)
+	}
+	return data, nil
+}
+
+// PutFileVariable stores data as a file variable, creating it or, when
+// variableID is set, replacing that variable. An empty mode keeps the current
+// mode, or uses the server default for new files. Replacing a variable also
+// replaces its description.
+func (v *VariablesController) PutFileVariable(projectID, environmentID, variableID string, key, filename, mode, description string, data []byte) (*EnvironmentVariableResponse, error) {
+	reqBody := map[string]any{
+		"key":         key,
+		"value":       base64.StdEncoding.EncodeToString(data),
+		"description": description,
+		"type":        VariableTypeFile,
+		"filename":    filename,
+	}
+	if mode != "" {
+		reqBody["mode"] = mode
+	}
+
+	method, path := "POST", fmt.Sprintf("/projects/%s/environments/%s/variables", projectID, environmentID)
+	if variableID != "" {
+		method, path = "PUT", path+"/"+variableID
+	}
+
+	resp, err := v.doRequest(method, path, reqBody, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var varResp EnvironmentVariableResponse
+	if err := v.decodeResponse(resp, &varResp); err != nil {
+		return nil, err
+	}
+
+	return &varResp, nil
+}
+
+// variablesQuery builds the query string of a variable list request, adding a
+// label parameter for each selector.
+func variablesQuery(query url.Values, labels []string) string {
+	for _, label := range labels {
+		query.Add("label", label)
+	}
+	if len(query) == 0 {
+		return ""
+	}
+	return "?" + query.Encode()
+}
+
+// ListEnvironmentVariables lists the variables of an environment with masked
+// values. labels holds selectors such as "team=payments" or "deprecated" that
+// every returned variable must match.
+func (v *VariablesController) ListEnvironmentVariables(projectID, environmentID string, labels []string) ([]EnvironmentVariableResponse, error) {
+	path := fmt.Sprintf("/projects/%s/environments/%s/variables", projectID, environmentID) + variablesQuery(url.Values{}, labels)
+	resp, err := v.doRequest("GET", path, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var variables []EnvironmentVariableResponse
+	if err := v.decodeResponse(resp, &variables); err != nil {
+		return nil, err
+	}
+
+	return variables, nil
+}
+
+// RevealEnvironmentVariables lists an environment's variables with plaintext values.
+// References are expanded unless raw is set. Each call is recorded in the
+// project's audit log.
+func (v *VariablesController) RevealEnvironmentVariables(projectID, environmentID string, raw bool, labels []string) ([]EnvironmentVariableResponse, error) {
+	query := url.Values{"reveal": {"true"}}
+	if raw {
+		query.Set("raw", "true")
+	}
+	path := fmt.Sprintf("/projects/%s/environments/%s/variables", projectID, environmentID) + variablesQuery(query, labels)
+	resp, err := v.doRequest("GET", path, nil, true)
 	if err != nil {
 		return nil, err
 	}
@@ -73,10 +255,34 @@ func (v *VariablesController) GetEnvironmentVariable(projectID, environmentID, v
 	return &varResp, nil
 }
 
-func (v *VariablesController) UpdateEnvironmentVariable(projectID, environmentID, variableID string, key, value string) (*EnvironmentVariableResponse, error) {
+// RevealEnvironmentVariable fetches a single variable with its plaintext value.
+// References are expanded unless raw is set. Each call is recorded in the
+// project's audit log.
+func (v *VariablesController) RevealEnvironmentVariable(projectID, environmentID, variableID string, raw bool) (*EnvironmentVariableResponse, error) {
+	path := fmt.Sprintf("/projects/%s/environments/%s/variables/%s/reveal", projectID, environmentID, variableID)
+	if raw {
+		path += "?raw=true"
+	}
+	resp, err := v.doRequest("POST", path, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var varResp EnvironmentVariableResponse
+	if err := v.decodeResponse(resp, &varResp); err != nil {
+		return nil, err
+	}
+
+	return &varResp, nil
+}
+
+// UpdateEnvironmentVariable replaces the key, value and description of a
+// variable. An empty description removes the current one.
+func (v *VariablesController) UpdateEnvironmentVariable(projectID, environmentID, variableID string, key, value, description string) (*EnvironmentVariableResponse, error) {
 	reqBody := map[string]any{
-		"key":   key,
-		"value": value,
+		"key":         key,
+		"value":       value,
+		"description": description,
 	}
 
 	resp, err := v.doRequest("PUT", fmt.Sprintf("/projects/%s/environments/%s/variables/%s", projectID, environmentID, variableID), reqBody, true)
@@ -92,6 +298,271 @@ func (v *VariablesController) UpdateEnvironmentVariable(projectID, environmentID
 	return &varResp, nil
 }
 
+// UpsertEnvironmentVariable sets the value for key, creating the variable if it
+// does not exist. The returned bool reports whether a new variable was created.
+func (v *VariablesController) UpsertEnvironmentVariable(projectID, environmentID string, key, value string) (*EnvironmentVariableResponse, bool, error) {
+	reqBody := map[string]any{
+		"value": value,
+	}
+
+	resp, err := v.doRequest("PUT", fmt.Sprintf("/projects/%s/environments/%s/variables/by-key/%s", projectID, environmentID, url.PathEscape(key)), reqBody, true)
+	if err != nil {
+		return nil, false, err
+	}
+	created := resp.StatusCode == http.StatusCreated
+
+	var varResp EnvironmentVariableResponse
+	if err := v.decodeResponse(resp, &varResp); err != nil {
+		return nil, false, err
+	}
+
+	return &varResp, created, nil
+}
+
+type VariableOperation struct {
+	Op          string `json:"op"`
+	Key         string `json:"key"`
+	Value       string `json:"value,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type VariableOperationResult struct {
+	Key    string `json:"key"`
+	Op     string `json:"op"`
+	Status string `json:"status"`
+}
+
+type BatchVariablesResponse struct {
+	Results []VariableOperationResult `json:"results"`
+	Created int                       `json:"created"`
+	Updated int                       `json:"updated"`
+	Deleted int                       `json:"deleted"`
+}
+
+// BatchEnvironmentVariables applies set and delete operations in a single
+// transaction. If any operation fails, none of them are applied.
+func (v *VariablesController) BatchEnvironmentVariables(projectID, environmentID string, operations []VariableOperation) (*BatchVariablesResponse, error) {
+	reqBody := map[string]any{
+		"operations": operations,
+	}
+
+	resp, err := v.doRequest("POST", fmt.Sprintf("/projects/%s/environments/%s/variables:batch", projectID, environmentID), reqBody, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var batchResp BatchVariablesResponse
+	if err := v.decodeResponse(resp, &batchResp); err != nil {
+		return nil, err
+	}
+
+	return &batchResp, nil
+}
+
+type ReplaceVariablesResponse struct {
+	Added     []string `json:"added"`
+	Changed   []string `json:"changed"`
+	Removed   []string `json:"removed"`
+	Unchanged int      `json:"unchanged"`
+	DryRun    bool     `json:"dry_run"`
+}
+
+// ReplaceEnvironmentVariables makes the environment match variables exactly,
+// removing any keys not present. With dryRun the changes are only reported.
+func (v *VariablesController) ReplaceEnvironmentVariables(projectID, environmentID string, variables map[string]string, dryRun bool) (*ReplaceVariablesResponse, error) {
+	reqBody := map[string]any{
+		"variables": variables,
+	}
+
+	path := fmt.Sprintf("/projects/%s/environments/%s/variables", projectID, environmentID)
+	if dryRun {
+		path += "?dry_run=true"
+	}
+
+	resp, err := v.doRequest("PUT", path, reqBody, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var replaceResp ReplaceVariablesResponse
+	if err := v.decodeResponse(resp, &replaceResp); err != nil {
+		return nil, err
+	}
+
+	return &replaceResp, nil
+}
+
+// SetEnvironmentVariableRotation replaces the rotation metadata of a variable.
+// Empty fields clear it.
+func (v *VariablesController) SetEnvironmentVariableRotation(projectID, environmentID, variableID string, rotation VariableRotation) (*EnvironmentVariableResponse, error) {
+	reqBody := map[string]any{
+		"rotate_after": rotation.RotateAfter,
+		"expires_at":   rotation.expiresAt(),
+	}
+
+	resp, err := v.doRequest("PUT", fmt.Sprintf("/projects/%s/environments/%s/variables/%s/rotation", projectID, environmentID, variableID), reqBody, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var varResp EnvironmentVariableResponse
+	if err := v.decodeResponse(resp, &varResp); err != nil {
+		return nil, err
+	}
+
+	return &varResp, nil
+}
+
+// SetEnvironmentVariableLabels replaces every label of a variable. An empty
+// map removes them all.
+func (v *VariablesController) SetEnvironmentVariableLabels(projectID, environmentID, variableID string, labels map[string]string) (*EnvironmentVariableResponse, error) {
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	reqBody := map[string]any{
+		"labels": labels,
+	}
+
+	resp, err := v.doRequest("PUT", fmt.Sprintf("/projects/%s/environments/%s/variables/%s/labels", projectID, environmentID, variableID), reqBody, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var varResp EnvironmentVariableResponse
+	if err := v.decodeResponse(resp, &varResp); err != nil {
+		return nil, err
+	}
+
+	return &varResp, nil
+}
+
+type ProjectRef struct {
+	ID   shared.ProjectID `json:"id"`
+	Name string           `json:"name"`
+}
+
+// Statuses of stale variables, most urgent first.
+const (
+	StaleStatusExpired         = "expired"
+	StaleStatusRotationOverdue = "rotation_overdue"
+	StaleStatusExpiring        = "expiring"
+	StaleStatusRotationDue     = "rotation_due"
+)
+
+type StaleVariableResponse struct {
+	ID          shared.EnvironmentVariableID `json:"id"`
+	Key         string                       `json:"key"`
+	Type        string                       `json:"type"`
+	Project     ProjectRef                   `json:"project"`
+	Environment EnvironmentRef               `json:"environment"`
+	Status      string                       `json:"status"`
+	DueAt       shared.Timestamp             `json:"due_at"`
+	RotateAfter string                       `json:"rotate_after"`
+	RotatedAt   shared.Timestamp             `json:"rotated_at"`
+	ExpiresAt   shared.Timestamp             `json:"expires_at"`
+}
+
+// Overdue reports whether the variable has already expired or is past its
+// rotation deadline.
+func (v StaleVariableResponse) Overdue() bool {
+	return v.Status == StaleStatusExpired || v.Status == StaleStatusRotationOverdue
+}
+
+// ListStaleVariables lists variables across every accessible project that have
+// expired or are overdue for rotation, or will be within the given duration.
+// An empty within uses the server default.
+func (v *VariablesController) ListStaleVariables(within string) ([]StaleVariableResponse, error) {
+	path := "/variables/stale"
+	if within != "" {
+		path += "?within=" + url.QueryEscape(within)
+	}
+
+	resp, err := v.doRequest("GET", path, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var variables []StaleVariableResponse
+	if err := v.decodeResponse(resp, &variables); err != nil {
+		return nil, err
+	}
+
+	return variables, nil
+}
+
+// VariableSearchResult is a variable found by SearchVariables. Origin is
+// "environment" or "project"; project-level variables have no Environment.
+type VariableSearchResult struct {
+	ID          string          `json:"id"`
+	Key         string          `json:"key"`
+	Type        string          `json:"type"`
+	Origin      string          `json:"origin"`
+	Project     ProjectRef      `json:"project"`
+	Environment *EnvironmentRef `json:"environment"`
+	ValueMatch  bool            `json:"value_match"`
+}
+
+// Fingerprint returns the hex encoded SHA-256 of a value, as compared by
+// SearchVariables. The value itself is never sent to the server.
+func Fingerprint(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// SearchVariables finds variables across every accessible project whose key
+// matches a glob pattern such as "*STRIPE*", whose value has the given
+// fingerprint, or both.
+func (v *VariablesController) SearchVariables(key, fingerprint string) ([]VariableSearchResult, error) {
+	query := url.Values{}
+	if key != "" {
+		query.Set("key", key)
+	}
+	if fingerprint != "" {
+		query.Set("fingerprint", fingerprint)
+	}
+
+	resp, err := v.doRequest("GET", "/variables/search?"+query.Encode(), nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []VariableSearchResult
+	if err := v.decodeResponse(resp, &results); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+type ReplaceValueResponse struct {
+	DryRun   bool                   `json:"dry_run"`
+	Replaced []VariableSearchResult `json:"replaced"`
+	Skipped  []VariableSearchResult `json:"skipped"`
+}
+
+// ReplaceValue replaces the value with the given fingerprint in every variable
+// the user can edit, across all projects, in a single transaction. With dryRun
+// the variables are listed without changing them.
+func (v *VariablesController) ReplaceValue(fingerprint, value string, dryRun bool) (*ReplaceValueResponse, error) {
+	reqBody := map[string]any{
+		"fingerprint": fingerprint,
+		"value":       value,
+		"dry_run":     dryRun,
+	}
+
+	resp, err := v.doRequest("POST", "/variables/replace-value", reqBody, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var replaceResp ReplaceValueResponse
+	if err := v.decodeResponse(resp, &replaceResp); err != nil {
+		return nil, err
+	}
+
+	return &replaceResp, nil
+}
+
 func (v *VariablesController) DeleteEnvironmentVariable(projectID, environmentID, variableID string) error {
 	resp, err := v.doRequest("DELETE", fmt.Sprintf("/projects/%s/environments/%s/variables/%s", projectID, environmentID, variableID), nil, true)
 	if err != nil {
@@ -111,3 +582,105 @@ func (v *VariablesController) DeleteEnvironmentVariable(projectID, environmentID
 
 	return nil
 }
+
+type ProjectVariableResponse struct {
+	ID          shared.ProjectVariableID `json:"id"`
+	ProjectID   shared.ProjectID         `json:"project_id"`
+	Key         string                   `json:"key"`
+	Value       string                   `json:"value"`
+	Description *string                  `json:"description"`
+	Redacted    bool                     `json:"redacted"`
+	Masked      bool                     `json:"masked"`
+	CreatedAt   shared.Timestamp         `json:"created_at"`
+	UpdatedAt   shared.Timestamp         `json:"updated_at"`
+}
+
+// DisplayValue returns the value for display, marking redacted and masked values clearly.
+func (v ProjectVariableResponse) DisplayValue() string {
+	if v.Redacted {
+		return RedactedValue
+	}
+	if v.Masked {
+		return MaskedValue
+	}
+	return v.Value
+}
+
+// ListProjectVariables lists the variables shared by every environment of a
+// project. Values are masked unless reveal is set, which is audited.
+func (v *VariablesController) ListProjectVariables(projectID string, reveal bool) ([]ProjectVariableResponse, error) {
+	path := fmt.Sprintf("/projects/%s/variables", projectID)
+	if reveal {
+		path += "?reveal=true"
+	}
+
+	resp, err := v.doRequest("GET", path, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var variables []ProjectVariableResponse
+	if err := v.decodeResponse(resp, &variables); err != nil {
+		return nil, err
+	}
+
+	return variables, nil
+}
+
+func (v *VariablesController) CreateProjectVariable(projectID string, key, value string) (*ProjectVariableResponse, error) {
+	reqBody := map[string]any{
+		"key":   key,
+		"value": value,
+	}
+
+	resp, err := v.doRequest("POST", fmt.Sprintf("/projects/%s/variables", projectID), reqBody, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var varResp ProjectVariableResponse
+	if err := v.decodeResponse(resp, &varResp); err != nil {
+		return nil, err
+	}
+
+	return &varResp, nil
+}
+
+func (v *VariablesController) UpdateProjectVariable(projectID, variableID string, key, value string) (*ProjectVariableResponse, error) {
+	reqBody := map[string]any{
+		"key":   key,
+		"value": value,
+	}
+
+	resp, err := v.doRequest("PUT", fmt.Sprintf("/projects/%s/variables/%s", projectID, variableID), reqBody, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var varResp ProjectVariableResponse
+	if err := v.decodeResponse(resp, &varResp); err != nil {
+		return nil, err
+	}
+
+	return &varResp, nil
+}
+
+func (v *VariablesController) DeleteProjectVariable(projectID, variableID string) error {
+	resp, err := v.doRequest("DELETE", fmt.Sprintf("/projects/%s/variables/%s", projectID, variableID), nil, true)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		var errResp ErrorResponse
+		json.NewDecoder(resp.Body).Decode(&errResp)
+		if errResp.Error != "" {
+			return fmt.Errorf("server error: %s", errResp.Error)
+		}
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
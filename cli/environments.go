@@ -2,12 +2,15 @@ package cli
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"os"
+	"strings"
 
 	cli "github.com/pressly/cli"
 	"ytsruh.com/envoy/cli/controllers"
 	"ytsruh.com/envoy/cli/prompts"
+	"ytsruh.com/envoy/cli/utils"
 	shared "ytsruh.com/envoy/shared"
 )
 
@@ -20,6 +23,9 @@ var environmentsCmd = &cli.Command{
 		getEnvironmentCmd,
 		updateEnvironmentCmd,
 		deleteEnvironmentCmd,
+		diffEnvironmentsCmd,
+		promoteEnvironmentCmd,
+		cloneEnvironmentCmd,
 	},
 }
 
@@ -27,7 +33,12 @@ var createEnvironmentCmd = &cli.Command{
 	Name:      "create",
 	ShortHelp: "Create a new environment",
 	Usage:     "envoy environments create [project_id] [flags]",
+	Flags: cli.FlagsFunc(func(f *flag.FlagSet) {
+		f.String("parent", "", "ID or name of an environment to inherit variables from")
+	}),
 	Exec: func(ctx context.Context, s *cli.State) error {
+		parent := cli.GetFlag[string](s, "parent")
+
 		client, err := controllers.RequireToken()
 		if err != nil {
 			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
@@ -72,7 +83,7 @@ var createEnvironmentCmd = &cli.Command{
 			os.Exit(1)
 		}
 
-		environment, err := client.CreateEnvironment(projectID, name, description)
+		environment, err := client.CreateEnvironment(projectID, name, description, parent)
 		if err != nil {
 			fmt.Fprintf(s.Stderr, "Failed to create environment: %v\n", err)
 			if err == shared.ErrExpiredToken {
@@ -141,6 +152,11 @@ var listEnvironmentsCmd = &cli.Command{
 			return nil
 		}
 
+		names := make(map[string]string, len(environments))
+		for _, env := range environments {
+			names[string(env.ID)] = env.Name
+		}
+
 		fmt.Fprintf(s.Stdout, "Found %d environment(s):\n\n", len(environments))
 		for _, env := range environments {
 			fmt.Fprintf(s.Stdout, "  ID: %s\n", env.ID)
@@ -148,6 +164,9 @@ var listEnvironmentsCmd = &cli.Command{
 			if env.Description != nil && *env.Description != "" {
 				fmt.Fprintf(s.Stdout, "  Description: %s\n", *env.Description)
 			}
+			if env.ParentID != nil {
+				fmt.Fprintf(s.Stdout, "  Inherits from: %s\n", names[*env.ParentID])
+			}
 			fmt.Fprintf(s.Stdout, "  Created: %s\n", env.CreatedAt)
 			fmt.Fprintln(s.Stdout, "")
 		}
@@ -199,6 +218,9 @@ var getEnvironmentCmd = &cli.Command{
 			if environment.Description != nil {
 				fmt.Fprintf(s.Stdout, "  Description: %s\n", *environment.Description)
 			}
+			if environment.ParentID != nil {
+				fmt.Fprintf(s.Stdout, "  Parent ID: %s\n", *environment.ParentID)
+			}
 			fmt.Fprintf(s.Stdout, "  Project ID: %s\n", environment.ProjectID)
 			fmt.Fprintf(s.Stdout, "  Created: %s\n", environment.CreatedAt)
 			fmt.Fprintf(s.Stdout, "  Updated: %s\n", environment.UpdatedAt)
@@ -234,6 +256,9 @@ var getEnvironmentCmd = &cli.Command{
 			if environment.Description != nil {
 				fmt.Fprintf(s.Stdout, "  Description: %s\n", *environment.Description)
 			}
+			if environment.ParentID != nil {
+				fmt.Fprintf(s.Stdout, "  Parent ID: %s\n", *environment.ParentID)
+			}
 			fmt.Fprintf(s.Stdout, "  Project ID: %s\n", environment.ProjectID)
 			fmt.Fprintf(s.Stdout, "  Created: %s\n", environment.CreatedAt)
 			fmt.Fprintf(s.Stdout, "  Updated: %s\n", environment.UpdatedAt)
@@ -246,7 +271,19 @@ var updateEnvironmentCmd = &cli.Command{
 	Name:      "update",
 	ShortHelp: "Update an environment",
 	Usage:     "envoy environments update [environment_id] [project_id] [flags]",
+	Flags: cli.FlagsFunc(func(f *flag.FlagSet) {
+		f.String("parent", "", "ID or name of an environment to inherit variables from")
+		f.Bool("no-parent", false, "Stop inheriting variables from the current parent")
+	}),
 	Exec: func(ctx context.Context, s *cli.State) error {
+		var parent *string
+		if cli.GetFlag[bool](s, "no-parent") {
+			none := ""
+			parent = &none
+		} else if p := cli.GetFlag[string](s, "parent"); p != "" {
+			parent = &p
+		}
+
 		client, err := controllers.RequireToken()
 		if err != nil {
 			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
@@ -295,7 +332,7 @@ var updateEnvironmentCmd = &cli.Command{
 				description = *environment.Description
 			}
 
-			updatedEnvironment, err := client.UpdateEnvironment(projectID, environmentID, name, description)
+			updatedEnvironment, err := client.UpdateEnvironment(projectID, environmentID, name, description, parent)
 			if err != nil {
 				fmt.Fprintf(s.Stderr, "Failed to update environment: %v\n", err)
 				if err == shared.ErrExpiredToken {
@@ -350,7 +387,7 @@ var updateEnvironmentCmd = &cli.Command{
 				description = *environment.Description
 			}
 
-			updatedEnvironment, err := client.UpdateEnvironment(projectID, environmentID, name, description)
+			updatedEnvironment, err := client.UpdateEnvironment(projectID, environmentID, name, description, parent)
 			if err != nil {
 				fmt.Fprintf(s.Stderr, "Failed to update environment: %v\n", err)
 				if err == shared.ErrExpiredToken {
@@ -479,3 +516,331 @@ var deleteEnvironmentCmd = &cli.Command{
 		return nil
 	},
 }
+
+var diffEnvironmentsCmd = &cli.Command{
+	Name:      "diff",
+	ShortHelp: "Show differences between two environments",
+	Usage:     "envoy environments diff [project_id] [base_environment] [target_environment] [flags]",
+	Flags: cli.FlagsFunc(func(f *flag.FlagSet) {
+		f.Bool("reveal", false, "Show plaintext values (recorded in the project audit log)")
+	}),
+	Exec: func(ctx context.Context, s *cli.State) error {
+		reveal := cli.GetFlag[bool](s, "reveal")
+
+		client, err := controllers.RequireToken()
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			if err == shared.ErrNoToken {
+				fmt.Fprintln(s.Stdout, "Please login first using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		var projectID, base, target string
+
+		if len(s.Args) == 3 {
+			projectID = s.Args[0]
+			base = s.Args[1]
+			target = s.Args[2]
+		} else if len(s.Args) > 0 {
+			fmt.Fprintln(s.Stderr, "Error: project_id and both environments are required")
+			fmt.Fprintln(s.Stderr, "Usage: envoy environments diff <project_id> <base_environment> <target_environment>")
+			os.Exit(1)
+		} else {
+			projectID, err = prompts.PromptForProject(client)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			base, err = prompts.PromptForEnvironment(client, projectID)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			target, err = prompts.PromptForEnvironment(client, projectID)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		diff, err := client.CompareEnvironments(projectID, base, target, reveal)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to compare environments: %v\n", err)
+			if err == shared.ErrExpiredToken {
+				fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		fmt.Fprintf(s.Stdout, "Comparing %s (base) with %s (target)\n\n", diff.Base.Name, diff.Target.Name)
+
+		if len(diff.OnlyInBase) == 0 && len(diff.OnlyInTarget) == 0 && len(diff.Changed) == 0 {
+			fmt.Fprintf(s.Stdout, "No differences (%d identical variable(s))\n", diff.Unchanged)
+			return nil
+		}
+
+		for _, v := range diff.OnlyInBase {
+			line := fmt.Sprintf("- %s", v.Key)
+			if !diff.Masked {
+				line += "=" + v.Value
+			}
+			fmt.Fprintf(s.Stdout, "  %s  (only in %s%s)\n", utils.Red(line), diff.Base.Name, describeCompareOrigin(v.Origin, v.InheritedFrom))
+		}
+		for _, v := range diff.OnlyInTarget {
+			line := fmt.Sprintf("+ %s", v.Key)
+			if !diff.Masked {
+				line += "=" + v.Value
+			}
+			fmt.Fprintf(s.Stdout, "  %s  (only in %s%s)\n", utils.Green(line), diff.Target.Name, describeCompareOrigin(v.Origin, v.InheritedFrom))
+		}
+		for _, v := range diff.Changed {
+			line := fmt.Sprintf("~ %s", v.Key)
+			if !diff.Masked {
+				line += fmt.Sprintf(": %s -> %s", v.BaseValue, v.TargetValue)
+			}
+			baseOrigin := describeCompareOrigin(v.BaseOrigin, v.BaseInheritedFrom)
+			targetOrigin := describeCompareOrigin(v.TargetOrigin, v.TargetInheritedFrom)
+			if baseOrigin != "" || targetOrigin != "" {
+				line += fmt.Sprintf("  (%s%s -> %s%s)", diff.Base.Name, baseOrigin, diff.Target.Name, targetOrigin)
+			}
+			fmt.Fprintf(s.Stdout, "  %s\n", utils.Yellow(line))
+		}
+
+		fmt.Fprintf(s.Stdout, "\n%d only in %s, %d only in %s, %d changed, %d identical\n",
+			len(diff.OnlyInBase), diff.Base.Name, len(diff.OnlyInTarget), diff.Target.Name, len(diff.Changed), diff.Unchanged)
+		return nil
+	},
+}
+
+var promoteEnvironmentCmd = &cli.Command{
+	Name:      "promote",
+	ShortHelp: "Copy variables from one environment to another",
+	Usage:     "envoy environments promote [project_id] [source_environment] [target_environment] [flags]",
+	Flags: cli.FlagsFunc(func(f *flag.FlagSet) {
+		f.String("keys", "", "Comma-separated keys to promote (default: all keys)")
+		f.String("policy", "skip", "How to handle keys that differ in the target: skip, overwrite or fail")
+		f.Bool("dry-run", false, "Show what would change without applying it")
+	}),
+	FlagOptions: []cli.FlagOption{
+		{Name: "keys", Short: "k"},
+		{Name: "policy", Short: "p"},
+	},
+	Exec: func(ctx context.Context, s *cli.State) error {
+		keysFlag := cli.GetFlag[string](s, "keys")
+		policy := cli.GetFlag[string](s, "policy")
+		dryRun := cli.GetFlag[bool](s, "dry-run")
+
+		if policy != "skip" && policy != "overwrite" && policy != "fail" {
+			fmt.Fprintln(s.Stderr, "Error: --policy must be one of skip, overwrite or fail")
+			os.Exit(1)
+		}
+
+		var keys []string
+		for _, key := range strings.Split(keysFlag, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				keys = append(keys, key)
+			}
+		}
+
+		client, err := controllers.RequireToken()
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			if err == shared.ErrNoToken {
+				fmt.Fprintln(s.Stdout, "Please login first using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		var projectID, source, target string
+
+		if len(s.Args) == 3 {
+			projectID = s.Args[0]
+			source = s.Args[1]
+			target = s.Args[2]
+		} else if len(s.Args) > 0 {
+			fmt.Fprintln(s.Stderr, "Error: project_id, source and target environments are required")
+			fmt.Fprintln(s.Stderr, "Usage: envoy environments promote <project_id> <source_environment> <target_environment>")
+			os.Exit(1)
+		} else {
+			projectID, err = prompts.PromptForProject(client)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			source, err = prompts.PromptForEnvironment(client, projectID)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			target, err = prompts.PromptForEnvironment(client, projectID)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		preview, err := client.PromoteEnvironment(projectID, source, target, keys, policy, true)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to preview promotion: %v\n", err)
+			if err == shared.ErrExpiredToken {
+				fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		fmt.Fprintf(s.Stdout, "Promoting %s -> %s (policy: %s)\n\n", preview.Source.Name, preview.Target.Name, preview.Policy)
+		for _, r := range preview.Results {
+			switch r.Action {
+			case "create":
+				fmt.Fprintf(s.Stdout, "  %s\n", utils.Green("+ "+r.Key))
+			case "overwrite":
+				fmt.Fprintf(s.Stdout, "  %s\n", utils.Yellow("~ "+r.Key))
+			case "conflict":
+				fmt.Fprintf(s.Stdout, "  %s  (differs in %s)\n", utils.Red("! "+r.Key), preview.Target.Name)
+			case "skip":
+				fmt.Fprintf(s.Stdout, "    %s  (differs in %s, skipped)\n", r.Key, preview.Target.Name)
+			}
+		}
+		fmt.Fprintf(s.Stdout, "\n%d to create, %d to overwrite, %d skipped, %d unchanged\n",
+			preview.Created, preview.Overwritten, preview.Skipped, preview.Unchanged)
+
+		if preview.Conflicts > 0 {
+			fmt.Fprintf(s.Stderr, "Error: %d key(s) differ in %s; rerun with --policy skip or --policy overwrite\n", preview.Conflicts, preview.Target.Name)
+			os.Exit(1)
+		}
+
+		if dryRun {
+			fmt.Fprintln(s.Stdout, "Dry run: no changes were applied")
+			return nil
+		}
+
+		if preview.Created+preview.Overwritten == 0 {
+			fmt.Fprintln(s.Stdout, "Nothing to promote")
+			return nil
+		}
+
+		confirmed, err := prompts.Confirm("Apply this promotion?")
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if !confirmed {
+			fmt.Fprintln(s.Stdout, "Promotion cancelled")
+			return nil
+		}
+
+		result, err := client.PromoteEnvironment(projectID, source, target, keys, policy, false)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to promote: %v\n", err)
+			fmt.Fprintln(s.Stderr, "No variables were changed")
+			if err == shared.ErrExpiredToken {
+				fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		fmt.Fprintf(s.Stdout, "Promoted %d variable(s) to %s\n", result.Created+result.Overwritten, result.Target.Name)
+		return nil
+	},
+}
+
+var cloneEnvironmentCmd = &cli.Command{
+	Name:      "clone",
+	ShortHelp: "Create a copy of an environment and its variables",
+	Usage:     "envoy environments clone [environment_id] [project_id] [flags]",
+	Flags: cli.FlagsFunc(func(f *flag.FlagSet) {
+		f.String("name", "", "Name of the new environment")
+		f.String("target-project", "", "ID of the project to create the copy in (default: same project)")
+		f.Bool("blank-values", false, "Copy keys only, leaving values empty")
+	}),
+	FlagOptions: []cli.FlagOption{
+		{Name: "name", Short: "n"},
+	},
+	Exec: func(ctx context.Context, s *cli.State) error {
+		name := cli.GetFlag[string](s, "name")
+		targetProjectID := cli.GetFlag[string](s, "target-project")
+		blankValues := cli.GetFlag[bool](s, "blank-values")
+
+		client, err := controllers.RequireToken()
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			if err == shared.ErrNoToken {
+				fmt.Fprintln(s.Stdout, "Please login first using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		var environmentID, projectID string
+
+		if len(s.Args) == 2 {
+			environmentID = s.Args[0]
+			projectID = s.Args[1]
+		} else if len(s.Args) == 1 {
+			fmt.Fprintln(s.Stderr, "Error: Both environment_id and project_id are required")
+			fmt.Fprintln(s.Stderr, "Usage: envoy environments clone <environment_id> <project_id>")
+			os.Exit(1)
+		} else {
+			projectID, err = prompts.PromptForProject(client)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			environmentID, err = prompts.PromptForEnvironment(client, projectID)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		if name == "" {
+			name, err = prompts.PromptString("New environment name", true)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		result, err := client.CloneEnvironment(projectID, environmentID, name, "", targetProjectID, blankValues)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to clone environment: %v\n", err)
+			if err == shared.ErrExpiredToken {
+				fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		fmt.Fprintln(s.Stdout, "Environment cloned successfully!")
+		fmt.Fprintf(s.Stdout, "  ID: %s\n", result.Environment.ID)
+		fmt.Fprintf(s.Stdout, "  Name: %s\n", result.Environment.Name)
+		fmt.Fprintf(s.Stdout, "  Project ID: %s\n", result.Environment.ProjectID)
+		if blankValues {
+			fmt.Fprintf(s.Stdout, "  Variables: %d (keys only)\n", result.VariablesCopied)
+			if len(result.SkippedKeys) > 0 {
+				fmt.Fprintf(s.Stdout, "  Skipped: %s (their type does not allow an empty value)\n", strings.Join(result.SkippedKeys, ", "))
+			}
+		} else {
+			fmt.Fprintf(s.Stdout, "  Variables: %d\n", result.VariablesCopied)
+		}
+		return nil
+	},
+}
+
+// describeCompareOrigin returns a suffix naming where a compared value comes
+// from, or an empty string when the environment defines it itself.
+func describeCompareOrigin(origin string, inheritedFrom *controllers.EnvironmentRef) string {
+	switch {
+	case inheritedFrom != nil:
+		return ", inherited from " + inheritedFrom.Name
+	case origin == "project":
+		return ", inherited from project"
+	default:
+		return ""
+	}
+}
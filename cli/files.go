@@ -0,0 +1,367 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	cli "github.com/pressly/cli"
+	"ytsruh.com/envoy/cli/controllers"
+	"ytsruh.com/envoy/cli/prompts"
+	shared "ytsruh.com/envoy/shared"
+)
+
+var filesCmd = &cli.Command{
+	Name:      "files",
+	ShortHelp: "Manage file variables such as certificates and keys",
+	SubCommands: []*cli.Command{
+		putFileCmd,
+		getFileCmd,
+		syncFilesCmd,
+	},
+}
+
+var putFileCmd = &cli.Command{
+	Name:      "put",
+	ShortHelp: "Store a file in an environment",
+	Usage:     "envoy files put <path> [project_id] [environment_id] [flags]",
+	Flags: cli.FlagsFunc(func(f *flag.FlagSet) {
+		f.String("key", "", "Variable key (default derived from the file name, e.g. TLS_CRT)")
+		f.String("name", "", "File name to write the file as (default the name of path)")
+		f.String("mode", "", "Permissions to write the file with (default those of path)")
+	}),
+	FlagOptions: []cli.FlagOption{
+		{Name: "key", Short: "k"},
+	},
+	Exec: func(ctx context.Context, s *cli.State) error {
+		key := cli.GetFlag[string](s, "key")
+		filename := cli.GetFlag[string](s, "name")
+		mode := cli.GetFlag[string](s, "mode")
+
+		client, err := controllers.RequireToken()
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			if err == shared.ErrNoToken {
+				fmt.Fprintln(s.Stdout, "Please login first using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		if len(s.Args) == 0 {
+			fmt.Fprintln(s.Stderr, "Error: path is required")
+			fmt.Fprintln(s.Stderr, "Usage: envoy files put <path> <project_id> <environment_id>")
+			os.Exit(1)
+		}
+		path := s.Args[0]
+		projectID, environmentID, err := fileEnvironmentArgs(client, s.Args[1:])
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			fmt.Fprintln(s.Stderr, "Usage: envoy files put <path> <project_id> <environment_id>")
+			os.Exit(1)
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to read file: %v\n", err)
+			os.Exit(1)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to read file: %v\n", err)
+			os.Exit(1)
+		}
+		if filename == "" {
+			filename = filepath.Base(path)
+		}
+		if key == "" {
+			key = fileKey(filename)
+		}
+		if mode == "" {
+			mode = fmt.Sprintf("%04o", info.Mode().Perm())
+		}
+
+		variables, err := client.ListEnvironmentVariables(projectID, environmentID, nil)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to list variables: %v\n", err)
+			if err == shared.ErrExpiredToken {
+				fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+		var variableID, description string
+		for _, v := range variables {
+			if v.Key != key || v.InheritedFrom != nil || v.Origin == "project" {
+				continue
+			}
+			if !v.IsFile() {
+				fmt.Fprintf(s.Stderr, "Error: %s already exists and is not a file variable\n", key)
+				os.Exit(1)
+			}
+			variableID = string(v.ID)
+			if v.Description != nil {
+				description = *v.Description
+			}
+		}
+
+		variable, err := client.PutFileVariable(projectID, environmentID, variableID, key, filename, mode, description, data)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to store file: %v\n", err)
+			if err == shared.ErrExpiredToken {
+				fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		fmt.Fprintf(s.Stdout, "Stored %s as %s (%d bytes, mode %s)\n", variable.Filename, variable.Key, len(data), variable.Mode)
+		return nil
+	},
+}
+
+var getFileCmd = &cli.Command{
+	Name:      "get",
+	ShortHelp: "Write a file variable to disk",
+	Usage:     "envoy files get <key> [project_id] [environment_id] [flags]",
+	Flags: cli.FlagsFunc(func(f *flag.FlagSet) {
+		f.String("output", "", "Path to write the file to (default its stored file name)")
+	}),
+	FlagOptions: []cli.FlagOption{
+		{Name: "output", Short: "o"},
+	},
+	Exec: func(ctx context.Context, s *cli.State) error {
+		output := cli.GetFlag[string](s, "output")
+
+		client, err := controllers.RequireToken()
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			if err == shared.ErrNoToken {
+				fmt.Fprintln(s.Stdout, "Please login first using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		if len(s.Args) == 0 {
+			fmt.Fprintln(s.Stderr, "Error: key is required")
+			fmt.Fprintln(s.Stderr, "Usage: envoy files get <key> <project_id> <environment_id>")
+			os.Exit(1)
+		}
+		key := s.Args[0]
+		projectID, environmentID, err := fileEnvironmentArgs(client, s.Args[1:])
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			fmt.Fprintln(s.Stderr, "Usage: envoy files get <key> <project_id> <environment_id>")
+			os.Exit(1)
+		}
+
+		variables, err := client.ListEnvironmentVariables(projectID, environmentID, nil)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to list variables: %v\n", err)
+			if err == shared.ErrExpiredToken {
+				fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+		var found *controllers.EnvironmentVariableResponse
+		for _, v := range variables {
+			if v.Key == key {
+				found = &v
+				break
+			}
+		}
+		if found == nil || !found.IsFile() {
+			fmt.Fprintf(s.Stderr, "Error: file variable %s not found\n", key)
+			os.Exit(1)
+		}
+
+		variable, err := revealFile(client, projectID, environmentID, *found)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to get file: %v\n", err)
+			if err == shared.ErrExpiredToken {
+				fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		if output == "" {
+			output = variable.Filename
+		}
+		size, err := writeFileVariable(output, *variable)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to write file: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Fprintf(s.Stdout, "Wrote %s to %s (%d bytes, mode %s)\n", variable.Key, output, size, variable.Mode)
+		return nil
+	},
+}
+
+var syncFilesCmd = &cli.Command{
+	Name:      "sync",
+	ShortHelp: "Write every file variable of an environment to disk",
+	Usage:     "envoy files sync [project_id] [environment_id] [flags]",
+	Flags: cli.FlagsFunc(func(f *flag.FlagSet) {
+		f.String("dir", ".", "Directory to write the files to")
+	}),
+	FlagOptions: []cli.FlagOption{
+		{Name: "dir", Short: "d"},
+	},
+	Exec: func(ctx context.Context, s *cli.State) error {
+		dir := cli.GetFlag[string](s, "dir")
+
+		client, err := controllers.RequireToken()
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			if err == shared.ErrNoToken {
+				fmt.Fprintln(s.Stdout, "Please login first using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		projectID, environmentID, err := fileEnvironmentArgs(client, s.Args)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			fmt.Fprintln(s.Stderr, "Usage: envoy files sync <project_id> <environment_id> [-d dir]")
+			os.Exit(1)
+		}
+
+		variables, err := client.ListEnvironmentVariables(projectID, environmentID, nil)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to list variables: %v\n", err)
+			if err == shared.ErrExpiredToken {
+				fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		var files []controllers.EnvironmentVariableResponse
+		owners := make(map[string]string)
+		for _, v := range variables {
+			if !v.IsFile() {
+				continue
+			}
+			if other, ok := owners[v.Filename]; ok {
+				fmt.Fprintf(s.Stderr, "Error: %s and %s are both written to %s\n", other, v.Key, v.Filename)
+				os.Exit(1)
+			}
+			owners[v.Filename] = v.Key
+			files = append(files, v)
+		}
+
+		if len(files) == 0 {
+			fmt.Fprintln(s.Stdout, "No file variables to sync")
+			return nil
+		}
+
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to create directory: %v\n", err)
+			os.Exit(1)
+		}
+		// Files are revealed one at a time so that only their values are
+		// read, and audited, rather than every variable of the environment.
+		for _, found := range files {
+			v, err := revealFile(client, projectID, environmentID, found)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Failed to get %s: %v\n", found.Key, err)
+				if err == shared.ErrExpiredToken {
+					fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+				}
+				os.Exit(1)
+			}
+			path := filepath.Join(dir, v.Filename)
+			size, err := writeFileVariable(path, *v)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Failed to write %s: %v\n", path, err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(s.Stdout, "  %s -> %s (%d bytes, mode %s)\n", v.Key, path, size, v.Mode)
+		}
+
+		fmt.Fprintf(s.Stdout, "Synced %d file(s) to %s\n", len(files), dir)
+		return nil
+	},
+}
+
+// revealFile reveals the raw contents of a file variable listed in an
+// environment. Inherited files are revealed from the environment that defines
+// them.
+func revealFile(client *controllers.Client, projectID, environmentID string, v controllers.EnvironmentVariableResponse) (*controllers.EnvironmentVariableResponse, error) {
+	sourceEnvironmentID := environmentID
+	if v.InheritedFrom != nil {
+		sourceEnvironmentID = string(v.InheritedFrom.ID)
+	}
+	return client.RevealEnvironmentVariable(projectID, sourceEnvironmentID, string(v.ID), true)
+}
+
+// fileEnvironmentArgs returns the project and environment given as arguments,
+// prompting for both when none are given.
+func fileEnvironmentArgs(client *controllers.Client, args []string) (string, string, error) {
+	switch len(args) {
+	case 0:
+		projectID, err := prompts.PromptForProject(client)
+		if err != nil {
+			return "", "", err
+		}
+		environmentID, err := prompts.PromptForEnvironment(client, projectID)
+		if err != nil {
+			return "", "", err
+		}
+		return projectID, environmentID, nil
+	case 2:
+		return args[0], args[1], nil
+	default:
+		return "", "", fmt.Errorf("both project_id and environment_id are required")
+	}
+}
+
+// fileKey derives a variable key from a file name, e.g. tls.crt becomes TLS_CRT.
+func fileKey(filename string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9'):
+			return r
+		default:
+			return '_'
+		}
+	}, filename)
+}
+
+// writeFileVariable writes the contents of a revealed file variable to path
+// with the variable's mode. The file is written to a temporary file and renamed
+// into place so the contents are never readable with looser permissions.
+func writeFileVariable(path string, v controllers.EnvironmentVariableResponse) (int, error) {
+	data, err := v.FileContents()
+	if err != nil {
+		return 0, err
+	}
+	mode, err := strconv.ParseUint(v.Mode, 8, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid mode %q for %s", v.Mode, v.Key)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return 0, err
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, err
+	}
+	if err := os.Chmod(tmp.Name(), os.FileMode(mode).Perm()); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
@@ -5,8 +5,22 @@ import "ytsruh.com/envoy/cli/controllers"
 type AuthResponse = controllers.AuthResponse
 type ProfileResponse = controllers.ProfileResponse
 type ProjectResponse = controllers.ProjectResponse
+type SchemaKey = controllers.SchemaKey
+type ProjectSchemaResponse = controllers.ProjectSchemaResponse
+type ValidateProjectSchemaResponse = controllers.ValidateProjectSchemaResponse
 type EnvironmentResponse = controllers.EnvironmentResponse
+type CompareEnvironmentsResponse = controllers.CompareEnvironmentsResponse
+type PromoteEnvironmentResponse = controllers.PromoteEnvironmentResponse
+type CloneEnvironmentResponse = controllers.CloneEnvironmentResponse
 type EnvironmentVariableResponse = controllers.EnvironmentVariableResponse
+type ProjectVariableResponse = controllers.ProjectVariableResponse
+type VariableOperation = controllers.VariableOperation
+type BatchVariablesResponse = controllers.BatchVariablesResponse
+type ReplaceVariablesResponse = controllers.ReplaceVariablesResponse
+type VariableRotation = controllers.VariableRotation
+type StaleVariableResponse = controllers.StaleVariableResponse
+type VariableSearchResult = controllers.VariableSearchResult
+type ReplaceValueResponse = controllers.ReplaceValueResponse
 
 type APIClient interface {
 	Register(name, email, password string) (*AuthResponse, error)
@@ -18,16 +32,38 @@ type APIClient interface {
 	GetProject(projectID string) (*ProjectResponse, error)
 	UpdateProject(projectID string, name, description, gitRepo string) (*ProjectResponse, error)
 	DeleteProject(projectID string) error
+	GetProjectSchema(projectID string) (*ProjectSchemaResponse, error)
+	UpdateProjectSchema(projectID string, keys []SchemaKey) (*ProjectSchemaResponse, error)
+	ValidateProjectSchema(projectID string, environment string) (*ValidateProjectSchemaResponse, error)
 
-	CreateEnvironment(projectID string, name, description string) (*EnvironmentResponse, error)
+	CreateEnvironment(projectID string, name, description, parent string) (*EnvironmentResponse, error)
 	ListEnvironments(projectID string) ([]EnvironmentResponse, error)
 	GetEnvironment(projectID string, environmentID string) (*EnvironmentResponse, error)
-	UpdateEnvironment(projectID string, environmentID string, name, description string) (*EnvironmentResponse, error)
+	UpdateEnvironment(projectID string, environmentID string, name, description string, parent *string) (*EnvironmentResponse, error)
 	DeleteEnvironment(projectID string, environmentID string) error
+	CompareEnvironments(projectID string, base, target string, reveal bool) (*CompareEnvironmentsResponse, error)
+	PromoteEnvironment(projectID string, source, target string, keys []string, policy string, dryRun bool) (*PromoteEnvironmentResponse, error)
+	CloneEnvironment(projectID string, environmentID string, name, description, targetProjectID string, blankValues bool) (*CloneEnvironmentResponse, error)
 
-	CreateEnvironmentVariable(projectID string, environmentID string, key, value string) (*EnvironmentVariableResponse, error)
-	ListEnvironmentVariables(projectID string, environmentID string) ([]EnvironmentVariableResponse, error)
+	CreateEnvironmentVariable(projectID string, environmentID string, key, value, description, variableType string, allowedValues []string, generate string, rotation VariableRotation, labels map[string]string) (*EnvironmentVariableResponse, error)
+	ListEnvironmentVariables(projectID string, environmentID string, labels []string) ([]EnvironmentVariableResponse, error)
+	RevealEnvironmentVariables(projectID string, environmentID string, raw bool, labels []string) ([]EnvironmentVariableResponse, error)
 	GetEnvironmentVariable(projectID string, environmentID string, variableID string) (*EnvironmentVariableResponse, error)
-	UpdateEnvironmentVariable(projectID string, environmentID string, variableID string, key, value string) (*EnvironmentVariableResponse, error)
+	RevealEnvironmentVariable(projectID string, environmentID string, variableID string, raw bool) (*EnvironmentVariableResponse, error)
+	UpdateEnvironmentVariable(projectID string, environmentID string, variableID string, key, value, description string) (*EnvironmentVariableResponse, error)
+	UpsertEnvironmentVariable(projectID string, environmentID string, key, value string) (*EnvironmentVariableResponse, bool, error)
+	BatchEnvironmentVariables(projectID string, environmentID string, operations []VariableOperation) (*BatchVariablesResponse, error)
+	ReplaceEnvironmentVariables(projectID string, environmentID string, variables map[string]string, dryRun bool) (*ReplaceVariablesResponse, error)
 	DeleteEnvironmentVariable(projectID string, environmentID string, variableID string) error
+	PutFileVariable(projectID string, environmentID string, variableID string, key, filename, mode, description string, data []byte) (*EnvironmentVariableResponse, error)
+	SetEnvironmentVariableLabels(projectID string, environmentID string, variableID string, labels map[string]string) (*EnvironmentVariableResponse, error)
+	SetEnvironmentVariableRotation(projectID string, environmentID string, variableID string, rotation VariableRotation) (*EnvironmentVariableResponse, error)
+	ListStaleVariables(within string) ([]StaleVariableResponse, error)
+	SearchVariables(key string, fingerprint string) ([]VariableSearchResult, error)
+	ReplaceValue(fingerprint string, value string, dryRun bool) (*ReplaceValueResponse, error)
+
+	ListProjectVariables(projectID string, reveal bool) ([]ProjectVariableResponse, error)
+	CreateProjectVariable(projectID string, key, value string) (*ProjectVariableResponse, error)
+	UpdateProjectVariable(projectID string, variableID string, key, value string) (*ProjectVariableResponse, error)
+	DeleteProjectVariable(projectID string, variableID string) error
 }
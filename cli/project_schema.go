@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	cli "github.com/pressly/cli"
+	"ytsruh.com/envoy/cli/controllers"
+	"ytsruh.com/envoy/cli/prompts"
+	"ytsruh.com/envoy/cli/utils"
+	shared "ytsruh.com/envoy/shared"
+)
+
+var schemaProjectCmd = &cli.Command{
+	Name:      "schema",
+	ShortHelp: "Show or replace the required-keys schema of a project",
+	Usage:     "envoy projects schema [project_id] [flags]",
+	Flags: cli.FlagsFunc(func(f *flag.FlagSet) {
+		f.String("file", "", "Replace the schema with the keys in this JSON file")
+	}),
+	FlagOptions: []cli.FlagOption{
+		{Name: "file", Short: "f"},
+	},
+	Exec: func(ctx context.Context, s *cli.State) error {
+		schemaFile := cli.GetFlag[string](s, "file")
+
+		client, err := controllers.RequireToken()
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			if err == shared.ErrNoToken {
+				fmt.Fprintln(s.Stdout, "Please login first using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		projectID, err := projectArg(client, s)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			fmt.Fprintln(s.Stderr, "Usage: envoy projects schema <project_id> [-f schema.json]")
+			os.Exit(1)
+		}
+
+		var schema *controllers.ProjectSchemaResponse
+		if schemaFile != "" {
+			data, err := os.ReadFile(schemaFile)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Failed to read schema file: %v\n", err)
+				os.Exit(1)
+			}
+			var keys controllers.ProjectSchemaResponse
+			if err := json.Unmarshal(data, &keys); err != nil {
+				fmt.Fprintf(s.Stderr, "Invalid schema file: %v\n", err)
+				os.Exit(1)
+			}
+			schema, err = client.UpdateProjectSchema(projectID, keys.Keys)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Failed to update schema: %v\n", err)
+				if err == shared.ErrExpiredToken {
+					fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+				}
+				os.Exit(1)
+			}
+			fmt.Fprintf(s.Stdout, "Schema updated with %d key(s)\n\n", len(schema.Keys))
+		} else {
+			schema, err = client.GetProjectSchema(projectID)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Failed to get schema: %v\n", err)
+				if err == shared.ErrExpiredToken {
+					fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+				}
+				os.Exit(1)
+			}
+		}
+
+		if len(schema.Keys) == 0 {
+			fmt.Fprintln(s.Stdout, "No schema defined")
+			return nil
+		}
+
+		for _, k := range schema.Keys {
+			required := "all environments"
+			if len(k.Environments) > 0 {
+				required = strings.Join(k.Environments, ", ")
+			}
+			fmt.Fprintf(s.Stdout, "  %s (%s), required in %s\n", k.Key, k.Type, required)
+			if k.Description != "" {
+				fmt.Fprintf(s.Stdout, "    %s\n", k.Description)
+			}
+		}
+		return nil
+	},
+}
+
+var checkProjectCmd = &cli.Command{
+	Name:      "check",
+	ShortHelp: "Check environments against the project schema",
+	Usage:     "envoy projects check [project_id] [flags]",
+	Flags: cli.FlagsFunc(func(f *flag.FlagSet) {
+		f.String("env", "", "Only check this environment (ID or name)")
+	}),
+	FlagOptions: []cli.FlagOption{
+		{Name: "env", Short: "e"},
+	},
+	Exec: func(ctx context.Context, s *cli.State) error {
+		environment := cli.GetFlag[string](s, "env")
+
+		client, err := controllers.RequireToken()
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			if err == shared.ErrNoToken {
+				fmt.Fprintln(s.Stdout, "Please login first using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		projectID, err := projectArg(client, s)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			fmt.Fprintln(s.Stderr, "Usage: envoy projects check <project_id> [-e environment]")
+			os.Exit(1)
+		}
+
+		report, err := client.ValidateProjectSchema(projectID, environment)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to check project: %v\n", err)
+			if err == shared.ErrExpiredToken {
+				fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		for _, env := range report.Environments {
+			if env.Valid {
+				fmt.Fprintf(s.Stdout, "%s %s\n", utils.Green("✓"), env.Environment.Name)
+				continue
+			}
+			fmt.Fprintf(s.Stdout, "%s %s\n", utils.Red("✗"), env.Environment.Name)
+			for _, key := range env.Missing {
+				fmt.Fprintf(s.Stdout, "  %s\n", utils.Red("missing "+key))
+			}
+			for _, v := range env.Invalid {
+				fmt.Fprintf(s.Stdout, "  %s\n", utils.Yellow(fmt.Sprintf("invalid %s: %s", v.Key, v.Error)))
+			}
+		}
+
+		if !report.Valid {
+			fmt.Fprintln(s.Stderr, "\nSchema check failed")
+			os.Exit(1)
+		}
+		fmt.Fprintln(s.Stdout, "\nAll environments match the schema")
+		return nil
+	},
+}
+
+// projectArg returns the project ID given as the only argument, or prompts for
+// one when no arguments are given.
+func projectArg(client *controllers.Client, s *cli.State) (string, error) {
+	switch len(s.Args) {
+	case 0:
+		return prompts.PromptForProject(client)
+	case 1:
+		return s.Args[0], nil
+	default:
+		return "", fmt.Errorf("too many arguments")
+	}
+}
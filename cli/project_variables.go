@@ -0,0 +1,244 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	cli "github.com/pressly/cli"
+	"ytsruh.com/envoy/cli/controllers"
+	"ytsruh.com/envoy/cli/prompts"
+	shared "ytsruh.com/envoy/shared"
+)
+
+var projectVariablesCmd = &cli.Command{
+	Name:      "variables",
+	ShortHelp: "Manage variables shared by every environment of a project",
+	SubCommands: []*cli.Command{
+		listProjectVariablesCmd,
+		setProjectVariableCmd,
+		deleteProjectVariableCmd,
+	},
+}
+
+var listProjectVariablesCmd = &cli.Command{
+	Name:      "list",
+	ShortHelp: "List project variables",
+	Usage:     "envoy projects variables list [project_id] [flags]",
+	Flags: cli.FlagsFunc(func(f *flag.FlagSet) {
+		f.Bool("reveal", false, "Show plaintext values (recorded in the project audit log)")
+	}),
+	Exec: func(ctx context.Context, s *cli.State) error {
+		reveal := cli.GetFlag[bool](s, "reveal")
+
+		client, err := controllers.RequireToken()
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			if err == shared.ErrNoToken {
+				fmt.Fprintln(s.Stdout, "Please login first using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		var projectID string
+		if len(s.Args) == 1 {
+			projectID = s.Args[0]
+		} else if len(s.Args) > 1 {
+			fmt.Fprintln(s.Stderr, "Error: Too many arguments")
+			fmt.Fprintln(s.Stderr, "Usage: envoy projects variables list <project_id>")
+			os.Exit(1)
+		} else {
+			projectID, err = prompts.PromptForProject(client)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		variables, err := client.ListProjectVariables(projectID, reveal)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to list project variables: %v\n", err)
+			if err == shared.ErrExpiredToken {
+				fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		if len(variables) == 0 {
+			fmt.Fprintln(s.Stdout, "No project variables found")
+			return nil
+		}
+
+		fmt.Fprintf(s.Stdout, "Found %d project variable(s):\n\n", len(variables))
+		for _, v := range variables {
+			fmt.Fprintf(s.Stdout, "  ID: %s\n", v.ID)
+			fmt.Fprintf(s.Stdout, "  Key: %s\n", v.Key)
+			fmt.Fprintf(s.Stdout, "  Value: %s\n", v.DisplayValue())
+			fmt.Fprintf(s.Stdout, "  Updated: %s\n", v.UpdatedAt)
+			fmt.Fprintln(s.Stdout, "")
+		}
+		return nil
+	},
+}
+
+var setProjectVariableCmd = &cli.Command{
+	Name:      "set",
+	ShortHelp: "Create or update a project variable",
+	Usage:     "envoy projects variables set [project_id] [key] [value]",
+	Exec: func(ctx context.Context, s *cli.State) error {
+		client, err := controllers.RequireToken()
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			if err == shared.ErrNoToken {
+				fmt.Fprintln(s.Stdout, "Please login first using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		var projectID, key, value string
+		if len(s.Args) == 3 {
+			projectID = s.Args[0]
+			key = s.Args[1]
+			value = s.Args[2]
+		} else if len(s.Args) > 0 {
+			fmt.Fprintln(s.Stderr, "Error: project_id, key and value are required")
+			fmt.Fprintln(s.Stderr, "Usage: envoy projects variables set <project_id> <key> <value>")
+			os.Exit(1)
+		} else {
+			projectID, err = prompts.PromptForProject(client)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			key, err = prompts.PromptString("Variable key", true)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			value, err = prompts.PromptString("Variable value", true)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		existing, err := findProjectVariable(client, projectID, key)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to list project variables: %v\n", err)
+			if err == shared.ErrExpiredToken {
+				fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		if existing != nil {
+			_, err = client.UpdateProjectVariable(projectID, string(existing.ID), key, value)
+		} else {
+			_, err = client.CreateProjectVariable(projectID, key, value)
+		}
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to save project variable: %v\n", err)
+			if err == shared.ErrExpiredToken {
+				fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		if existing != nil {
+			fmt.Fprintf(s.Stdout, "Project variable %s updated\n", key)
+		} else {
+			fmt.Fprintf(s.Stdout, "Project variable %s created\n", key)
+		}
+		return nil
+	},
+}
+
+var deleteProjectVariableCmd = &cli.Command{
+	Name:      "delete",
+	ShortHelp: "Delete a project variable",
+	Usage:     "envoy projects variables delete [project_id] [key]",
+	Exec: func(ctx context.Context, s *cli.State) error {
+		client, err := controllers.RequireToken()
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			if err == shared.ErrNoToken {
+				fmt.Fprintln(s.Stdout, "Please login first using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		var projectID, key string
+		if len(s.Args) == 2 {
+			projectID = s.Args[0]
+			key = s.Args[1]
+		} else if len(s.Args) > 0 {
+			fmt.Fprintln(s.Stderr, "Error: Both project_id and key are required")
+			fmt.Fprintln(s.Stderr, "Usage: envoy projects variables delete <project_id> <key>")
+			os.Exit(1)
+		} else {
+			projectID, err = prompts.PromptForProject(client)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			key, err = prompts.PromptString("Variable key", true)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		existing, err := findProjectVariable(client, projectID, key)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to list project variables: %v\n", err)
+			if err == shared.ErrExpiredToken {
+				fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+		if existing == nil {
+			fmt.Fprintf(s.Stderr, "Error: project variable %s not found\n", key)
+			os.Exit(1)
+		}
+
+		confirmed, err := prompts.Confirm(fmt.Sprintf("Delete project variable %s from every environment?", key))
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if !confirmed {
+			fmt.Fprintln(s.Stdout, "Deletion cancelled")
+			return nil
+		}
+
+		if err := client.DeleteProjectVariable(projectID, string(existing.ID)); err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to delete project variable: %v\n", err)
+			if err == shared.ErrExpiredToken {
+				fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		fmt.Fprintf(s.Stdout, "Project variable %s deleted\n", key)
+		return nil
+	},
+}
+
+// findProjectVariable returns the project variable with the given key, or nil
+// if the project does not define it.
+func findProjectVariable(client *controllers.Client, projectID, key string) (*controllers.ProjectVariableResponse, error) {
+	variables, err := client.ListProjectVariables(projectID, false)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range variables {
+		if v.Key == key {
+			return &v, nil
+		}
+	}
+	return nil, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	cli "github.com/pressly/cli"
 	"ytsruh.com/envoy/cli/controllers"
@@ -21,6 +22,12 @@ var projectsCmd = &cli.Command{
 		getProjectCmd,
 		updateProjectCmd,
 		deleteProjectCmd,
+		requestAccessCmd,
+		accessRequestsCmd,
+		elevateCmd,
+		projectVariablesCmd,
+		schemaProjectCmd,
+		checkProjectCmd,
 	},
 }
 
@@ -430,3 +437,226 @@ var deleteProjectCmd = &cli.Command{
 		return nil
 	},
 }
+
+var requestAccessCmd = &cli.Command{
+	Name:      "request-access",
+	ShortHelp: "Request access to a project by its git repository",
+	Usage:     "envoy projects request-access [owner/repo] [flags]",
+	Exec: func(ctx context.Context, s *cli.State) error {
+		client, err := controllers.RequireToken()
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			if err == shared.ErrNoToken {
+				fmt.Fprintln(s.Stdout, "Please login first using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		var gitRepo string
+		if len(s.Args) == 1 {
+			gitRepo = s.Args[0]
+		} else {
+			gitRepo, err = utils.GetGitRepoString()
+			if err != nil {
+				fmt.Fprintf(s.Stdout, "Warning: Could not detect git repository: %v\n", err)
+			}
+			if gitRepo == "" {
+				gitRepo, err = prompts.PromptString("Git repository (owner/repo)", true)
+				if err != nil {
+					fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+			} else {
+				fmt.Fprintf(s.Stdout, "Detected git repository: %s\n", gitRepo)
+			}
+		}
+
+		projects, err := client.DiscoverProjects(gitRepo)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to find projects: %v\n", err)
+			if err == shared.ErrExpiredToken {
+				fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		if len(projects) == 0 {
+			fmt.Fprintf(s.Stdout, "No projects found for git repository '%s'\n", gitRepo)
+			return nil
+		}
+
+		projectID := string(projects[0].ID)
+		projectName := projects[0].Name
+		if len(projects) > 1 {
+			options := make([]prompts.SelectOption, len(projects))
+			for i, p := range projects {
+				options[i] = prompts.SelectOption{Label: p.Name, Value: string(p.ID)}
+			}
+			projectID, err = prompts.PromptSelect("Multiple projects use this repository. Select a project", options, true)
+			if err != nil {
+				fmt.Fprintln(s.Stdout, "Operation cancelled")
+				return nil
+			}
+			for _, p := range projects {
+				if string(p.ID) == projectID {
+					projectName = p.Name
+				}
+			}
+		}
+
+		fmt.Fprintf(s.Stdout, "Project: %s (ID: %s)\n", projectName, projectID)
+
+		role, err := prompts.PromptRole("Select the role to request")
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		message, err := prompts.PromptString("Message for the project owner (optional)", false)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		accessRequest, err := client.RequestAccess(projectID, role, message)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to request access: %v\n", err)
+			if err == shared.ErrExpiredToken {
+				fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		fmt.Fprintln(s.Stdout, "Access request submitted!")
+		fmt.Fprintf(s.Stdout, "  Request ID: %s\n", accessRequest.ID)
+		fmt.Fprintf(s.Stdout, "  Role: %s\n", accessRequest.Role)
+		fmt.Fprintf(s.Stdout, "  Status: %s\n", accessRequest.Status)
+		fmt.Fprintln(s.Stdout, "The project owner will need to approve your request.")
+		return nil
+	},
+}
+
+var accessRequestsCmd = &cli.Command{
+	Name:      "access-requests",
+	ShortHelp: "Review pending access requests for a project",
+	Usage:     "envoy projects access-requests [project_id] [flags]",
+	Exec: func(ctx context.Context, s *cli.State) error {
+		client, err := controllers.RequireToken()
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			if err == shared.ErrNoToken {
+				fmt.Fprintln(s.Stdout, "Please login first using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		var projectID string
+		if len(s.Args) == 1 {
+			projectID = s.Args[0]
+		} else {
+			projectID, err = prompts.PromptForProject(client)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		accessRequests, err := client.ListAccessRequests(projectID)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to list access requests: %v\n", err)
+			if err == shared.ErrExpiredToken {
+				fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		if len(accessRequests) == 0 {
+			fmt.Fprintln(s.Stdout, "No pending access requests")
+			return nil
+		}
+
+		fmt.Fprintf(s.Stdout, "Found %d pending access request(s):\n", len(accessRequests))
+		for _, r := range accessRequests {
+			fmt.Fprintln(s.Stdout, "")
+			fmt.Fprintf(s.Stdout, "  User: %s (%s)\n", r.UserName, r.UserEmail)
+			fmt.Fprintf(s.Stdout, "  Role: %s\n", r.Role)
+			if r.Message != nil && *r.Message != "" {
+				fmt.Fprintf(s.Stdout, "  Message: %s\n", *r.Message)
+			}
+			fmt.Fprintf(s.Stdout, "  Requested: %s\n", r.CreatedAt)
+
+			action, err := prompts.PromptSelect("Review this request", []prompts.SelectOption{
+				{Label: "Approve", Value: "approve"},
+				{Label: "Deny", Value: "deny"},
+				{Label: "Skip", Value: "skip"},
+			}, false)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			switch action {
+			case "approve":
+				if _, err := client.ApproveAccessRequest(projectID, r.ID); err != nil {
+					fmt.Fprintf(s.Stderr, "Failed to approve request: %v\n", err)
+					continue
+				}
+				fmt.Fprintf(s.Stdout, "Approved: %s now has %s access\n", r.UserEmail, r.Role)
+			case "deny":
+				if _, err := client.DenyAccessRequest(projectID, r.ID); err != nil {
+					fmt.Fprintf(s.Stderr, "Failed to deny request: %v\n", err)
+					continue
+				}
+				fmt.Fprintf(s.Stdout, "Denied request from %s\n", r.UserEmail)
+			default:
+				fmt.Fprintln(s.Stdout, "Skipped")
+			}
+		}
+		return nil
+	},
+}
+
+var elevateCmd = &cli.Command{
+	Name:      "elevate",
+	ShortHelp: "Temporarily elevate your access to editor for one hour",
+	Usage:     "envoy projects elevate [id] [flags]",
+	Exec: func(ctx context.Context, s *cli.State) error {
+		client, err := controllers.RequireToken()
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			if err == shared.ErrNoToken {
+				fmt.Fprintln(s.Stdout, "Please login first using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		var projectID string
+		if len(s.Args) == 1 {
+			projectID = s.Args[0]
+		} else {
+			projectID, err = prompts.PromptForProject(client)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		reason, err := prompts.PromptString("Reason for elevated access", true)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		elevation, err := client.ElevateAccess(projectID, reason)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to elevate access: %v\n", err)
+			if err == shared.ErrExpiredToken {
+				fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		fmt.Fprintf(s.Stdout, "Access elevated to %s until %s\n", elevation.Role, elevation.ElevatedUntil.Local().Format(time.RFC1123))
+		return nil
+	},
+}
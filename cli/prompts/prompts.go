@@ -231,7 +231,7 @@ func PromptForEnvironment(client *controllers.Client, projectID string) (string,
 }
 
 func PromptForVariable(client *controllers.Client, projectID, environmentID string) (string, error) {
-	variables, err := client.ListEnvironmentVariables(projectID, environmentID)
+	variables, err := client.ListEnvironmentVariables(projectID, environmentID, nil)
 	if err != nil {
 		return "", err
 	}
@@ -240,12 +240,20 @@ func PromptForVariable(client *controllers.Client, projectID, environmentID stri
 		return "", fmt.Errorf("no variables found in this environment")
 	}
 
-	options := make([]SelectOption, len(variables))
-	for i, v := range variables {
-		options[i] = SelectOption{
-			Label: fmt.Sprintf("%s = %s", v.Key, v.Value),
-			Value: string(v.ID),
+	// Inherited and project variables belong to a parent environment or the
+	// project and cannot be selected for changes here.
+	var options []SelectOption
+	for _, v := range variables {
+		if v.InheritedFrom != nil || v.Origin == "project" {
+			continue
 		}
+		options = append(options, SelectOption{
+			Label: fmt.Sprintf("%s = %s", v.Key, v.DisplayValue()),
+			Value: string(v.ID),
+		})
+	}
+	if len(options) == 0 {
+		return "", fmt.Errorf("no variables defined in this environment")
 	}
 
 	return PromptSelect("Select a variable", options, true)
@@ -253,6 +261,7 @@ func PromptForVariable(client *controllers.Client, projectID, environmentID stri
 
 func PromptRole(prompt string) (string, error) {
 	return PromptSelect(prompt, []SelectOption{
+		{Label: "Metadata (keys only, values hidden)", Value: "metadata"},
 		{Label: "Viewer (read-only access)", Value: "viewer"},
 		{Label: "Editor (read and write access)", Value: "editor"},
 	}, false)
@@ -20,7 +20,11 @@ var Root = &cli.Command{
 		projectsCmd,
 		environmentsCmd,
 		environmentVariablesCmd,
+		filesCmd,
+		searchCmd,
+		rotateValueCmd,
 		usersCmd,
+		adminCmd,
 	},
 }
 
@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	cli "github.com/pressly/cli"
+	"ytsruh.com/envoy/cli/controllers"
+	"ytsruh.com/envoy/cli/prompts"
+	"ytsruh.com/envoy/cli/utils"
+	shared "ytsruh.com/envoy/shared"
+)
+
+var rotateValueCmd = &cli.Command{
+	Name:      "rotate-value",
+	ShortHelp: "Replace a leaked value in every variable that holds it, across all your projects",
+	Usage:     "envoy rotate-value [flags]",
+	Flags: cli.FlagsFunc(func(f *flag.FlagSet) {
+		f.String("file", "", "Replace the contents of this file instead of prompting for the old value")
+		f.String("fingerprint", "", "SHA-256 fingerprint of the old value instead of prompting for it")
+		f.String("new-file", "", "Read the new value from this file instead of prompting for it")
+		f.Bool("dry-run", false, "List the variables that would change without changing them")
+	}),
+	Exec: func(ctx context.Context, s *cli.State) error {
+		file := cli.GetFlag[string](s, "file")
+		fingerprint := cli.GetFlag[string](s, "fingerprint")
+		newFile := cli.GetFlag[string](s, "new-file")
+		dryRun := cli.GetFlag[bool](s, "dry-run")
+
+		if file != "" && fingerprint != "" {
+			fmt.Fprintln(s.Stderr, "Error: --file and --fingerprint cannot be combined")
+			os.Exit(1)
+		}
+
+		client, err := controllers.RequireToken()
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			if err == shared.ErrNoToken {
+				fmt.Fprintln(s.Stdout, "Please login first using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		fingerprint, err = readFingerprint(true, file, fingerprint, "Old value")
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		var value string
+		if newFile != "" {
+			data, err := os.ReadFile(newFile)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Failed to read file: %v\n", err)
+				os.Exit(1)
+			}
+			value = string(data)
+		} else {
+			value, err = prompts.PromptPassword("New value")
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		preview, err := client.ReplaceValue(fingerprint, value, true)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to preview replacement: %v\n", err)
+			if err == shared.ErrExpiredToken {
+				fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		if len(preview.Replaced) == 0 && len(preview.Skipped) == 0 {
+			fmt.Fprintln(s.Stdout, "No variables hold this value")
+			return nil
+		}
+
+		for _, r := range preview.Replaced {
+			fmt.Fprintf(s.Stdout, "  %s\n", utils.Yellow("~ "+searchLocation(r)))
+		}
+		for _, r := range preview.Skipped {
+			fmt.Fprintf(s.Stdout, "    %s  (no editor access, skipped)\n", searchLocation(r))
+		}
+		fmt.Fprintf(s.Stdout, "\n%d to replace, %d skipped\n", len(preview.Replaced), len(preview.Skipped))
+		if len(preview.Skipped) > 0 {
+			fmt.Fprintln(s.Stderr, "Warning: ask an editor of the skipped projects to replace the value there too")
+		}
+
+		if dryRun {
+			fmt.Fprintln(s.Stdout, "Dry run: no changes were applied")
+			return nil
+		}
+
+		if len(preview.Replaced) == 0 {
+			fmt.Fprintln(s.Stdout, "Nothing to replace")
+			return nil
+		}
+
+		confirmed, err := prompts.Confirm("Replace the value in these variables?")
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if !confirmed {
+			fmt.Fprintln(s.Stdout, "Replacement cancelled")
+			return nil
+		}
+
+		result, err := client.ReplaceValue(fingerprint, value, false)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to replace value: %v\n", err)
+			fmt.Fprintln(s.Stderr, "No variables were changed")
+			if err == shared.ErrExpiredToken {
+				fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		fmt.Fprintf(s.Stdout, "Replaced the value in %d variable(s)\n", len(result.Replaced))
+		return nil
+	},
+}
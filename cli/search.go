@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	cli "github.com/pressly/cli"
+	"ytsruh.com/envoy/cli/controllers"
+	"ytsruh.com/envoy/cli/prompts"
+	"ytsruh.com/envoy/cli/utils"
+	shared "ytsruh.com/envoy/shared"
+)
+
+var searchCmd = &cli.Command{
+	Name:      "search",
+	ShortHelp: "Find where a key or value is used across all your projects",
+	Usage:     "envoy search [key_pattern] [flags]",
+	Flags: cli.FlagsFunc(func(f *flag.FlagSet) {
+		f.Bool("value", false, "Prompt for a value and find the variables that hold it")
+		f.String("file", "", "Find the file variables with the same contents as this file")
+		f.String("fingerprint", "", "Find the variables whose value has this SHA-256 fingerprint")
+	}),
+	Exec: func(ctx context.Context, s *cli.State) error {
+		promptValue := cli.GetFlag[bool](s, "value")
+		file := cli.GetFlag[string](s, "file")
+		fingerprint := cli.GetFlag[string](s, "fingerprint")
+
+		client, err := controllers.RequireToken()
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			if err == shared.ErrNoToken {
+				fmt.Fprintln(s.Stdout, "Please login first using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		var pattern string
+		if len(s.Args) == 1 {
+			pattern = s.Args[0]
+		} else if len(s.Args) > 1 {
+			fmt.Fprintln(s.Stderr, "Error: only one key pattern can be given")
+			fmt.Fprintln(s.Stderr, "Usage: envoy search <key_pattern> [--value | --file <path> | --fingerprint <sha256>]")
+			os.Exit(1)
+		}
+
+		given := 0
+		for _, set := range []bool{promptValue, file != "", fingerprint != ""} {
+			if set {
+				given++
+			}
+		}
+		if given > 1 {
+			fmt.Fprintln(s.Stderr, "Error: --value, --file and --fingerprint cannot be combined")
+			os.Exit(1)
+		}
+		if pattern == "" && given == 0 {
+			fmt.Fprintln(s.Stderr, "Error: a key pattern, --value, --file or --fingerprint is required")
+			fmt.Fprintln(s.Stderr, "Usage: envoy search <key_pattern> [--value | --file <path> | --fingerprint <sha256>]")
+			os.Exit(1)
+		}
+
+		fingerprint, err = readFingerprint(promptValue, file, fingerprint, "Value to search for")
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		results, err := client.SearchVariables(pattern, fingerprint)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to search variables: %v\n", err)
+			if err == shared.ErrExpiredToken {
+				fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		if len(results) == 0 {
+			fmt.Fprintln(s.Stdout, "No matching variables found")
+			return nil
+		}
+
+		projects := make(map[shared.ProjectID]bool)
+		for _, r := range results {
+			projects[r.Project.ID] = true
+			line := searchLocation(r)
+			if r.ValueMatch {
+				line = utils.Yellow(line + " value matches")
+			}
+			fmt.Fprintf(s.Stdout, "  %s\n", line)
+			fmt.Fprintf(s.Stdout, "    ID: %s\n", r.ID)
+		}
+
+		fmt.Fprintf(s.Stdout, "\nFound %d variable(s) in %d project(s)\n", len(results), len(projects))
+		if fingerprint != "" {
+			fmt.Fprintln(s.Stdout, "Projects where the value matched have recorded this search in their audit log")
+		}
+		return nil
+	},
+}
+
+// searchLocation describes where a variable found by a search is defined, e.g.
+// "billing/production STRIPE_KEY".
+func searchLocation(r controllers.VariableSearchResult) string {
+	if r.Environment != nil {
+		return fmt.Sprintf("%s/%s %s", r.Project.Name, r.Environment.Name, r.Key)
+	}
+	return fmt.Sprintf("%s (project) %s", r.Project.Name, r.Key)
+}
+
+// readFingerprint returns the fingerprint of the value to look for: the given
+// fingerprint, the hash of file, or with promptValue the hash of a value typed
+// at prompt. Values are hashed locally so that only the fingerprint is sent.
+func readFingerprint(promptValue bool, file, fingerprint, prompt string) (string, error) {
+	switch {
+	case fingerprint != "":
+		return fingerprint, nil
+	case file != "":
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("failed to read file: %w", err)
+		}
+		return controllers.Fingerprint(data), nil
+	case promptValue:
+		value, err := prompts.PromptPassword(prompt)
+		if err != nil {
+			return "", err
+		}
+		return controllers.Fingerprint([]byte(value)), nil
+	default:
+		return "", nil
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	cli "github.com/pressly/cli"
 	"ytsruh.com/envoy/cli/controllers"
@@ -123,7 +124,14 @@ var listMembersCmd = &cli.Command{
 		fmt.Fprintf(s.Stdout, "Members of '%s':\n\n", projectName)
 		for _, m := range members {
 			fmt.Fprintf(s.Stdout, "  User ID: %s\n", m.UserID)
-			fmt.Fprintf(s.Stdout, "  Role:    %s\n\n", m.Role)
+			fmt.Fprintf(s.Stdout, "  Role:    %s\n", m.Role)
+			if m.ElevatedUntil != nil {
+				fmt.Fprintf(s.Stdout, "  Elevated to editor until: %s\n", m.ElevatedUntil.Local().Format(time.RFC1123))
+			}
+			if m.ExpiresAt != nil {
+				fmt.Fprintf(s.Stdout, "  Expires: %s\n", m.ExpiresAt.Local().Format(time.RFC1123))
+			}
+			fmt.Fprintln(s.Stdout)
 		}
 		return nil
 	},
@@ -188,19 +196,28 @@ var addMemberCmd = &cli.Command{
 
 		user := users[0]
 
-		role, err := prompts.PromptRole("Enter role (viewer/editor)")
+		role, err := prompts.PromptRole("Select role")
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		expiresIn, err := prompts.PromptString("Access duration, e.g. 12h or 30d (leave blank for permanent)", false)
 		if err != nil {
 			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
 
-		err = client.ProjectsController.AddMember(string(project.ID), string(user.UserID), role)
+		err = client.ProjectsController.AddMember(string(project.ID), string(user.UserID), role, expiresIn)
 		if err != nil {
 			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
 
 		fmt.Fprintf(s.Stdout, "Successfully added %s (%s) to project '%s' as %s\n", user.Name, user.Email, projectName, role)
+		if expiresIn != "" {
+			fmt.Fprintf(s.Stdout, "Access expires in %s\n", expiresIn)
+		}
 		return nil
 	},
 }
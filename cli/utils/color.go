@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"os"
+
+	"golang.org/x/term"
+)
+
+const (
+	colorReset  = "\033[0m"
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+)
+
+// colorEnabled reports whether stdout is a terminal and NO_COLOR is not set.
+func colorEnabled() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+func colorize(color, s string) string {
+	if !colorEnabled() {
+		return s
+	}
+	return color + s + colorReset
+}
+
+// Green formats s in green when writing to a terminal.
+func Green(s string) string {
+	return colorize(colorGreen, s)
+}
+
+// Red formats s in red when writing to a terminal.
+func Red(s string) string {
+	return colorize(colorRed, s)
+}
+
+// Yellow formats s in yellow when writing to a terminal.
+func Yellow(s string) string {
+	return colorize(colorYellow, s)
+}
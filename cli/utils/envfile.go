@@ -8,15 +8,30 @@ import (
 	"strings"
 )
 
+// ParseEnvFile reads KEY=value lines, skipping blanks and # comments. Values may
+// be wrapped in single or double quotes. A quote that ends its line opens a
+// value spanning the following lines up to the closing quote, as used for
+// certificates and keys.
 func ParseEnvFile(path string) (map[string]string, error) {
+	variables, _, err := ParseEnvFileWithComments(path)
+	return variables, err
+}
+
+// ParseEnvFileWithComments reads a file like ParseEnvFile and also returns the
+// trailing comment of each line that has one, keyed by variable, such as
+// "Stripe API key" for KEY=value # Stripe API key. A # only starts a comment
+// after whitespace or a closing quote, so KEY=a#b keeps its value.
+func ParseEnvFileWithComments(path string) (map[string]string, map[string]string, error) {
 	file, err := os.Open(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open .env file: %w", err)
+		return nil, nil, fmt.Errorf("failed to open .env file: %w", err)
 	}
 	defer file.Close()
 
 	variables := make(map[string]string)
+	comments := make(map[string]string)
 	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
 	lineNumber := 0
 
 	for scanner.Scan() {
@@ -29,33 +44,147 @@ func ParseEnvFile(path string) (map[string]string, error) {
 
 		parts := strings.SplitN(line, "=", 2)
 		if len(parts) != 2 {
-			return nil, fmt.Errorf("invalid format on line %d: %s", lineNumber, line)
+			return nil, nil, fmt.Errorf("invalid format on line %d: %s", lineNumber, line)
 		}
 
 		key := strings.TrimSpace(parts[0])
 		value := strings.TrimSpace(parts[1])
 
-		if value == "" {
-			variables[key] = ""
+		if value == "" || (value[0] != '"' && value[0] != '\'') {
+			value, comment := splitComment(parts[1])
+			variables[key] = value
+			if comment != "" {
+				comments[key] = comment
+			}
 			continue
 		}
 
-		if (strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`)) ||
-			(strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'")) {
-			value = value[1 : len(value)-1]
+		quote := value[0]
+		if len(value) > 1 {
+			if closingQuote(value[1:], quote) < 0 {
+				return nil, nil, fmt.Errorf("unterminated quoted value for %s on line %d", key, lineNumber)
+			}
+			inner, comment, ok := closeQuote(value[1:], quote)
+			if !ok {
+				return nil, nil, fmt.Errorf("unexpected text after quoted value for %s on line %d", key, lineNumber)
+			}
+			variables[key] = inner
+			if comment != "" {
+				comments[key] = comment
+			}
+			continue
 		}
 
-		variables[key] = value
+		start := lineNumber
+		var lines []string
+		closed := false
+		for scanner.Scan() {
+			lineNumber++
+			next := strings.TrimRight(scanner.Text(), " \t\r")
+			if closingQuote(next, quote) >= 0 {
+				inner, comment, ok := closeQuote(next, quote)
+				if !ok {
+					return nil, nil, fmt.Errorf("unexpected text after quoted value for %s on line %d", key, lineNumber)
+				}
+				lines = append(lines, inner)
+				if comment != "" {
+					comments[key] = comment
+				}
+				closed = true
+				break
+			}
+			lines = append(lines, unescapeQuoted(next, quote))
+		}
+		if !closed {
+			if err := scanner.Err(); err != nil {
+				return nil, nil, fmt.Errorf("error reading .env file: %w", err)
+			}
+			return nil, nil, fmt.Errorf("unterminated quoted value for %s starting on line %d", key, start)
+		}
+		variables[key] = strings.Join(lines, "\n")
 	}
 
 	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading .env file: %w", err)
+		return nil, nil, fmt.Errorf("error reading .env file: %w", err)
+	}
+
+	return variables, comments, nil
+}
+
+// splitComment splits an unquoted value from a trailing comment, which starts
+// at the first # preceded by whitespace.
+func splitComment(s string) (value, comment string) {
+	for i := 1; i < len(s); i++ {
+		if s[i] == '#' && (s[i-1] == ' ' || s[i-1] == '\t') {
+			return strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+1:])
+		}
+	}
+	return strings.TrimSpace(s), ""
+}
+
+// closingQuote returns the index of the first quote in s that closes a quoted
+// value, or -1 if there is none. Within double quotes \" is a literal quote and
+// never closes the value.
+func closingQuote(s string, quote byte) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == quote && (quote != '"' || !escaped(s, i)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// closeQuote splits s at its closing quote, returning the unescaped text before
+// the quote and the comment after it. ok is false when s has no closing quote
+// or the quote is followed by anything other than a comment.
+func closeQuote(s string, quote byte) (inner, comment string, ok bool) {
+	i := closingQuote(s, quote)
+	if i < 0 {
+		return "", "", false
 	}
+	rest := strings.TrimSpace(s[i+1:])
+	if rest != "" && !strings.HasPrefix(rest, "#") {
+		return "", "", false
+	}
+	if rest != "" {
+		comment = strings.TrimSpace(rest[1:])
+	}
+	return unescapeQuoted(s[:i], quote), comment, true
+}
 
-	return variables, nil
+// escaped reports whether the byte at i is preceded by an odd number of
+// backslashes.
+func escaped(s string, i int) bool {
+	n := 0
+	for i--; i >= 0 && s[i] == '\\'; i-- {
+		n++
+	}
+	return n%2 == 1
+}
+
+// unescapeQuoted replaces \" and \\ within a double-quoted value with " and \.
+// Other backslashes, and everything within single quotes, are kept as written.
+func unescapeQuoted(s string, quote byte) string {
+	if quote != '"' || !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && (s[i+1] == '"' || s[i+1] == '\\') {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
 }
 
 func WriteEnvFile(path string, variables map[string]string) error {
+	return WriteEnvFileWithComments(path, variables, nil)
+}
+
+// WriteEnvFileWithComments writes variables sorted by key, preceding each key
+// that has an entry in comments with that comment as one or more "# " lines.
+func WriteEnvFileWithComments(path string, variables map[string]string, comments map[string]string) error {
 	file, err := os.Create(path)
 	if err != nil {
 		return fmt.Errorf("failed to create .env file: %w", err)
@@ -69,10 +198,39 @@ func WriteEnvFile(path string, variables map[string]string) error {
 	sort.Strings(keys)
 
 	for _, key := range keys {
-		if _, err := fmt.Fprintf(file, "%s=%s\n", key, variables[key]); err != nil {
+		if comment := comments[key]; comment != "" {
+			for _, line := range strings.Split(comment, "\n") {
+				if _, err := fmt.Fprintf(file, "# %s\n", line); err != nil {
+					return fmt.Errorf("failed to write to .env file: %w", err)
+				}
+			}
+		}
+		if _, err := fmt.Fprintf(file, "%s=%s\n", key, quoteEnvValue(variables[key])); err != nil {
 			return fmt.Errorf("failed to write to .env file: %w", err)
 		}
 	}
 
 	return nil
 }
+
+// quoteEnvValue wraps multi-line values, values that would otherwise be read
+// as having a trailing comment, and values with surrounding spaces or a leading
+// quote in quotes so ParseEnvFile reads them back whole. Multi-line values
+// start on the line after the opening quote. Values containing only double
+// quotes use single quotes, which need no escaping; others use double quotes
+// with " and \ escaped. Other values are written unchanged.
+func quoteEnvValue(value string) string {
+	multiline := strings.Contains(value, "\n")
+	if !multiline && !strings.Contains(value, " #") && !strings.Contains(value, "\t#") &&
+		!strings.HasPrefix(value, `"`) && !strings.HasPrefix(value, "'") && strings.TrimSpace(value) == value {
+		return value
+	}
+	quote, quoted := `"`, strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
+	if strings.Contains(value, `"`) && !strings.Contains(value, "'") {
+		quote, quoted = "'", value
+	}
+	if multiline {
+		return quote + "\n" + quoted + quote
+	}
+	return quote + quoted + quote
+}
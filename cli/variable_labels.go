@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	cli "github.com/pressly/cli"
+	"ytsruh.com/envoy/cli/controllers"
+	"ytsruh.com/envoy/cli/prompts"
+	shared "ytsruh.com/envoy/shared"
+)
+
+var labelVariableCmd = &cli.Command{
+	Name:      "label",
+	ShortHelp: "Show, add or remove labels on a variable",
+	Usage:     "envoy variables label [variable_id] [project_id] [environment_id] [flags]",
+	Flags: cli.FlagsFunc(func(f *flag.FlagSet) {
+		f.String("set", "", "Comma-separated labels to add or change, e.g. team=payments,component=db")
+		f.String("remove", "", "Comma-separated label names to remove")
+	}),
+	Exec: func(ctx context.Context, s *cli.State) error {
+		set, err := parseLabels(cli.GetFlag[string](s, "set"))
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		remove := splitList(cli.GetFlag[string](s, "remove"))
+
+		client, err := controllers.RequireToken()
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			if err == shared.ErrNoToken {
+				fmt.Fprintln(s.Stdout, "Please login first using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		var variableID, projectID, environmentID string
+		if len(s.Args) == 3 {
+			variableID = s.Args[0]
+			projectID = s.Args[1]
+			environmentID = s.Args[2]
+		} else if len(s.Args) > 0 {
+			fmt.Fprintln(s.Stderr, "Error: All three arguments are required: variable_id, project_id, and environment_id")
+			fmt.Fprintln(s.Stderr, "Usage: envoy variables label <variable_id> <project_id> <environment_id>")
+			os.Exit(1)
+		} else {
+			projectID, err = prompts.PromptForProject(client)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			environmentID, err = prompts.PromptForEnvironment(client, projectID)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			variableID, err = prompts.PromptForVariable(client, projectID, environmentID)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		variable, err := client.GetEnvironmentVariable(projectID, environmentID, variableID)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to get variable: %v\n", err)
+			if err == shared.ErrExpiredToken {
+				fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		if len(set) > 0 || len(remove) > 0 {
+			// The server replaces every label, so merge the changes into the
+			// current set.
+			labels := make(map[string]string, len(variable.Labels)+len(set))
+			for name, value := range variable.Labels {
+				labels[name] = value
+			}
+			for _, name := range remove {
+				delete(labels, name)
+			}
+			for name, value := range set {
+				labels[name] = value
+			}
+
+			variable, err = client.SetEnvironmentVariableLabels(projectID, environmentID, variableID, labels)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Failed to update labels: %v\n", err)
+				if err == shared.ErrExpiredToken {
+					fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+				}
+				os.Exit(1)
+			}
+			fmt.Fprintln(s.Stdout, "Labels updated successfully!")
+		}
+
+		fmt.Fprintf(s.Stdout, "  Key: %s\n", variable.Key)
+		if len(variable.Labels) == 0 {
+			fmt.Fprintln(s.Stdout, "  No labels set")
+			return nil
+		}
+		printLabels(s.Stdout, *variable)
+		return nil
+	},
+}
+
+// splitList splits a comma-separated flag value, dropping empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseLabels parses a comma-separated list of name=value labels. A name
+// without a value sets an empty label.
+func parseLabels(s string) (map[string]string, error) {
+	items := splitList(s)
+	if len(items) == 0 {
+		return nil, nil
+	}
+	labels := make(map[string]string, len(items))
+	for _, item := range items {
+		name, value, _ := strings.Cut(item, "=")
+		if name == "" {
+			return nil, fmt.Errorf("invalid label %q, use name=value", item)
+		}
+		labels[name] = value
+	}
+	return labels, nil
+}
+
+// formatLabels returns labels as "name=value" pairs sorted by name.
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for name, value := range labels {
+		pairs = append(pairs, name+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
+
+func printLabels(w io.Writer, v controllers.EnvironmentVariableResponse) {
+	if len(v.Labels) > 0 {
+		fmt.Fprintf(w, "  Labels: %s\n", formatLabels(v.Labels))
+	}
+}
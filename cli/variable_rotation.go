@@ -0,0 +1,233 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	cli "github.com/pressly/cli"
+	"ytsruh.com/envoy/cli/controllers"
+	"ytsruh.com/envoy/cli/prompts"
+	"ytsruh.com/envoy/cli/utils"
+	shared "ytsruh.com/envoy/shared"
+)
+
+// clearRotation is the flag value that removes a rotation interval or expiry.
+const clearRotation = "none"
+
+var rotationVariableCmd = &cli.Command{
+	Name:      "rotation",
+	ShortHelp: "Show or set when a variable must be rotated or expires",
+	Usage:     "envoy variables rotation [variable_id] [project_id] [environment_id] [flags]",
+	Flags: cli.FlagsFunc(func(f *flag.FlagSet) {
+		f.String("rotate-after", "", "Require the value to be rotated after this long, e.g. 90d, or 'none'")
+		f.String("expires", "", "Date the value expires, e.g. 2027-01-31, or 'none'")
+	}),
+	Exec: func(ctx context.Context, s *cli.State) error {
+		rotateAfter := cli.GetFlag[string](s, "rotate-after")
+		expires := cli.GetFlag[string](s, "expires")
+
+		client, err := controllers.RequireToken()
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			if err == shared.ErrNoToken {
+				fmt.Fprintln(s.Stdout, "Please login first using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		var variableID, projectID, environmentID string
+		if len(s.Args) == 3 {
+			variableID = s.Args[0]
+			projectID = s.Args[1]
+			environmentID = s.Args[2]
+		} else if len(s.Args) > 0 {
+			fmt.Fprintln(s.Stderr, "Error: All three arguments are required: variable_id, project_id, and environment_id")
+			fmt.Fprintln(s.Stderr, "Usage: envoy variables rotation <variable_id> <project_id> <environment_id>")
+			os.Exit(1)
+		} else {
+			projectID, err = prompts.PromptForProject(client)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			environmentID, err = prompts.PromptForEnvironment(client, projectID)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			variableID, err = prompts.PromptForVariable(client, projectID, environmentID)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		variable, err := client.GetEnvironmentVariable(projectID, environmentID, variableID)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to get variable: %v\n", err)
+			if err == shared.ErrExpiredToken {
+				fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		if rotateAfter != "" || expires != "" {
+			// Flags that were not given keep their current value.
+			rotation := controllers.VariableRotation{
+				RotateAfter: variable.RotateAfter,
+				ExpiresAt:   variable.ExpiresAt.ToTime(),
+			}
+			switch rotateAfter {
+			case "":
+			case clearRotation:
+				rotation.RotateAfter = ""
+			default:
+				rotation.RotateAfter = rotateAfter
+			}
+			switch expires {
+			case "":
+			case clearRotation:
+				rotation.ExpiresAt = time.Time{}
+			default:
+				rotation.ExpiresAt, err = parseExpiry(expires)
+				if err != nil {
+					fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+			}
+
+			variable, err = client.SetEnvironmentVariableRotation(projectID, environmentID, variableID, rotation)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Failed to update rotation: %v\n", err)
+				if err == shared.ErrExpiredToken {
+					fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+				}
+				os.Exit(1)
+			}
+			fmt.Fprintln(s.Stdout, "Rotation updated successfully!")
+		}
+
+		fmt.Fprintf(s.Stdout, "  Key: %s\n", variable.Key)
+		if variable.RotateAfter == "" && variable.ExpiresAt.ToTime().IsZero() {
+			fmt.Fprintln(s.Stdout, "  No rotation or expiry set")
+			return nil
+		}
+		printRotation(s.Stdout, *variable)
+		return nil
+	},
+}
+
+var staleVariablesCmd = &cli.Command{
+	Name:      "stale",
+	ShortHelp: "List expired secrets and secrets due for rotation across your projects",
+	Usage:     "envoy variables stale [flags]",
+	Flags: cli.FlagsFunc(func(f *flag.FlagSet) {
+		f.String("within", "", "Also list secrets that expire or are due within this long (default 14d)")
+	}),
+	Exec: func(ctx context.Context, s *cli.State) error {
+		within := cli.GetFlag[string](s, "within")
+
+		client, err := controllers.RequireToken()
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			if err == shared.ErrNoToken {
+				fmt.Fprintln(s.Stdout, "Please login first using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		variables, err := client.ListStaleVariables(within)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to list stale variables: %v\n", err)
+			if err == shared.ErrExpiredToken {
+				fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		if len(variables) == 0 {
+			fmt.Fprintln(s.Stdout, "No variables are expired or due for rotation")
+			return nil
+		}
+
+		overdue := 0
+		for _, v := range variables {
+			location := fmt.Sprintf("%s/%s %s", v.Project.Name, v.Environment.Name, v.Key)
+			due := formatDate(v.DueAt)
+			switch v.Status {
+			case controllers.StaleStatusExpired:
+				fmt.Fprintf(s.Stdout, "%s %s\n", utils.Red("✗"), utils.Red(fmt.Sprintf("%s expired %s", location, due)))
+			case controllers.StaleStatusRotationOverdue:
+				fmt.Fprintf(s.Stdout, "%s %s\n", utils.Red("✗"), utils.Red(fmt.Sprintf("%s rotation overdue since %s (every %s)", location, due, v.RotateAfter)))
+			case controllers.StaleStatusExpiring:
+				fmt.Fprintf(s.Stdout, "%s %s\n", utils.Yellow("!"), utils.Yellow(fmt.Sprintf("%s expires %s", location, due)))
+			default:
+				fmt.Fprintf(s.Stdout, "%s %s\n", utils.Yellow("!"), utils.Yellow(fmt.Sprintf("%s rotation due %s (every %s)", location, due, v.RotateAfter)))
+			}
+			if v.Overdue() {
+				overdue++
+			}
+		}
+
+		fmt.Fprintf(s.Stdout, "\n%d overdue, %d due soon\n", overdue, len(variables)-overdue)
+		if overdue > 0 {
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+// parseRotationFlags reads the --rotate-after and --expires flags.
+func parseRotationFlags(s *cli.State) (controllers.VariableRotation, error) {
+	rotation := controllers.VariableRotation{RotateAfter: cli.GetFlag[string](s, "rotate-after")}
+	if expires := cli.GetFlag[string](s, "expires"); expires != "" {
+		expiresAt, err := parseExpiry(expires)
+		if err != nil {
+			return rotation, err
+		}
+		rotation.ExpiresAt = expiresAt
+	}
+	return rotation, nil
+}
+
+// parseExpiry accepts a date such as 2027-01-31, meaning the start of that day
+// in local time, or a full RFC3339 timestamp.
+func parseExpiry(s string) (time.Time, error) {
+	if t, err := time.ParseInLocation(time.DateOnly, s, time.Local); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid expiry %q, use a date such as 2027-01-31", s)
+	}
+	return t, nil
+}
+
+func formatDate(t shared.Timestamp) string {
+	return t.ToTime().Local().Format(time.DateOnly)
+}
+
+// printRotation prints when a variable must be rotated and when it expires,
+// flagging deadlines that have passed.
+func printRotation(w io.Writer, v controllers.EnvironmentVariableResponse) {
+	if v.RotateAfter != "" {
+		line := fmt.Sprintf("%s (every %s)", formatDate(v.RotateBy), v.RotateAfter)
+		if v.RotationOverdue {
+			line = utils.Red(line + " overdue")
+		}
+		fmt.Fprintf(w, "  Rotate by: %s\n", line)
+	}
+	if !v.ExpiresAt.ToTime().IsZero() {
+		line := formatDate(v.ExpiresAt)
+		if v.Expired {
+			line = utils.Red(line + " expired")
+		}
+		fmt.Fprintf(w, "  Expires: %s\n", line)
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"slices"
+	"sort"
 	"strings"
 
 	cli "github.com/pressly/cli"
@@ -20,11 +23,16 @@ var environmentVariablesCmd = &cli.Command{
 	SubCommands: []*cli.Command{
 		importVariablesCmd,
 		exportVariablesCmd,
+		exampleVariablesCmd,
+		pushVariablesCmd,
 		createVariableCmd,
 		listVariablesCmd,
 		getVariableCmd,
 		updateVariableCmd,
 		deleteVariableCmd,
+		rotationVariableCmd,
+		staleVariablesCmd,
+		labelVariableCmd,
 	},
 }
 
@@ -131,20 +139,30 @@ var importVariablesCmd = &cli.Command{
 			os.Exit(1)
 		}
 
-		variables, err := utils.ParseEnvFile(importFile)
+		variables, comments, err := utils.ParseEnvFileWithComments(importFile)
 		if err != nil {
 			fmt.Fprintf(s.Stderr, "Failed to parse file '%s': %v\n", importFile, err)
 			os.Exit(1)
 		}
 
+		skipEmptyValues(s.Stderr, variables)
 		if len(variables) == 0 {
 			fmt.Fprintf(s.Stdout, "No variables found in %s\n", importFile)
 			return nil
 		}
 
 		fmt.Fprintf(s.Stdout, "Found %d variable(s) in %s:\n\n", len(variables), importFile)
-		for key, value := range variables {
-			fmt.Fprintf(s.Stdout, "  %s=%s\n", key, value)
+		keys := make([]string, 0, len(variables))
+		for key := range variables {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if comment := comments[key]; comment != "" {
+				fmt.Fprintf(s.Stdout, "  %s  # %s\n", key, comment)
+			} else {
+				fmt.Fprintf(s.Stdout, "  %s\n", key)
+			}
 		}
 		fmt.Fprintln(s.Stdout, "")
 
@@ -159,20 +177,24 @@ var importVariablesCmd = &cli.Command{
 			return nil
 		}
 
-		created := 0
-		updated := 0
+		operations := make([]controllers.VariableOperation, 0, len(variables))
 		for key, value := range variables {
-			_, err := client.CreateEnvironmentVariable(projectID, environmentID, key, value)
-			if err != nil {
-				fmt.Fprintf(s.Stderr, "Failed to import variable %s: %v\n", key, err)
-				continue
+			operations = append(operations, controllers.VariableOperation{Op: "set", Key: key, Value: value, Description: comments[key]})
+		}
+
+		result, err := client.BatchEnvironmentVariables(projectID, environmentID, operations)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to import variables: %v\n", err)
+			fmt.Fprintln(s.Stderr, "No variables were changed")
+			if err == shared.ErrExpiredToken {
+				fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
 			}
-			created++
+			os.Exit(1)
 		}
 
-		fmt.Fprintf(s.Stdout, "Successfully imported %d variable(s)\n", created)
-		if updated > 0 {
-			fmt.Fprintf(s.Stdout, "Updated %d variable(s)\n", updated)
+		fmt.Fprintf(s.Stdout, "Successfully imported %d variable(s)\n", result.Created)
+		if result.Updated > 0 {
+			fmt.Fprintf(s.Stdout, "Updated %d variable(s)\n", result.Updated)
 		}
 		return nil
 	},
@@ -183,12 +205,14 @@ var exportVariablesCmd = &cli.Command{
 	ShortHelp: "Export variables to .env file",
 	Flags: cli.FlagsFunc(func(f *flag.FlagSet) {
 		f.String("file", "", "Path to the export file (default: .env.<environment_name>)")
+		f.String("label", "", "Only export variables with these labels, e.g. team=payments,component=db")
 	}),
 	FlagOptions: []cli.FlagOption{
 		{Name: "file", Short: "f"},
 	},
 	Exec: func(ctx context.Context, s *cli.State) error {
 		exportFile := cli.GetFlag[string](s, "file")
+		labels := splitList(cli.GetFlag[string](s, "label"))
 
 		client, err := controllers.RequireToken()
 		if err != nil {
@@ -277,7 +301,7 @@ var exportVariablesCmd = &cli.Command{
 			outputFilename = exportFile
 		}
 
-		variables, err := client.ListEnvironmentVariables(projectID, environmentID)
+		variables, err := client.RevealEnvironmentVariables(projectID, environmentID, false, labels)
 		if err != nil {
 			fmt.Fprintf(s.Stderr, "Failed to list variables: %v\n", err)
 			if err == shared.ErrExpiredToken {
@@ -285,6 +309,11 @@ var exportVariablesCmd = &cli.Command{
 			}
 			os.Exit(1)
 		}
+		for _, v := range variables {
+			if v.ResolutionError != "" {
+				fmt.Fprintf(s.Stderr, "Warning: %s exported unresolved: %s\n", v.Key, v.ResolutionError)
+			}
+		}
 
 		if len(variables) == 0 {
 			fmt.Fprintln(s.Stdout, "No variables to export")
@@ -305,7 +334,16 @@ var exportVariablesCmd = &cli.Command{
 		}
 
 		variablesMap := make(map[string]string)
+		skippedFiles := 0
 		for _, v := range variables {
+			if v.Redacted {
+				fmt.Fprintln(s.Stderr, "Error: variable values are redacted for your access level and cannot be exported")
+				os.Exit(1)
+			}
+			if v.IsFile() {
+				skippedFiles++
+				continue
+			}
 			variablesMap[v.Key] = v.Value
 		}
 
@@ -314,7 +352,307 @@ var exportVariablesCmd = &cli.Command{
 			os.Exit(1)
 		}
 
-		fmt.Fprintf(s.Stdout, "Exported %d variable(s) to %s\n", len(variables), outputFilename)
+		fmt.Fprintf(s.Stdout, "Exported %d variable(s) to %s\n", len(variablesMap), outputFilename)
+		if skippedFiles > 0 {
+			fmt.Fprintf(s.Stdout, "Skipped %d file variable(s); write them with 'envoy files sync'\n", skippedFiles)
+		}
+		origins := make(map[string]int)
+		for _, v := range variables {
+			origins[v.Origin]++
+		}
+		if origins["inherited"] > 0 || origins["project"] > 0 {
+			fmt.Fprintf(s.Stdout, "  %d defined in the environment, %d inherited from parents, %d from the project\n",
+				origins["environment"], origins["inherited"], origins["project"])
+		}
+		return nil
+	},
+}
+
+var exampleVariablesCmd = &cli.Command{
+	Name:      "example",
+	ShortHelp: "Write a .env.example with keys and descriptions but no values",
+	Usage:     "envoy variables example [project_id] [environment_id] [flags]",
+	Flags: cli.FlagsFunc(func(f *flag.FlagSet) {
+		f.String("file", ".env.example", "Path to the example file")
+		f.Bool("schema", false, "Use the project schema instead of an environment")
+	}),
+	FlagOptions: []cli.FlagOption{
+		{Name: "file", Short: "f"},
+	},
+	Exec: func(ctx context.Context, s *cli.State) error {
+		exampleFile := cli.GetFlag[string](s, "file")
+		fromSchema := cli.GetFlag[bool](s, "schema")
+
+		client, err := controllers.RequireToken()
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			if err == shared.ErrNoToken {
+				fmt.Fprintln(s.Stdout, "Please login first using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		var projectID, environmentID string
+		switch {
+		case fromSchema && len(s.Args) == 1:
+			projectID = s.Args[0]
+		case !fromSchema && len(s.Args) == 2:
+			projectID = s.Args[0]
+			environmentID = s.Args[1]
+		case len(s.Args) > 0:
+			fmt.Fprintln(s.Stderr, "Error: project_id and environment_id are required, or only project_id with --schema")
+			fmt.Fprintln(s.Stderr, "Usage: envoy variables example <project_id> <environment_id>")
+			fmt.Fprintln(s.Stderr, "       envoy variables example --schema <project_id>")
+			os.Exit(1)
+		default:
+			projectID, err = prompts.PromptForProject(client)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if !fromSchema {
+				environmentID, err = prompts.PromptForEnvironment(client, projectID)
+				if err != nil {
+					fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+			}
+		}
+
+		values := make(map[string]string)
+		comments := make(map[string]string)
+		if fromSchema {
+			schema, err := client.GetProjectSchema(projectID)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Failed to get schema: %v\n", err)
+				if err == shared.ErrExpiredToken {
+					fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+				}
+				os.Exit(1)
+			}
+			for _, k := range schema.Keys {
+				values[k.Key] = examplePlaceholder(k.Type, nil)
+				comments[k.Key] = exampleComment(k.Description, k.Type, nil)
+			}
+		} else {
+			// Values are never revealed: the example only needs keys and metadata.
+			variables, err := client.ListEnvironmentVariables(projectID, environmentID, nil)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Failed to list variables: %v\n", err)
+				if err == shared.ErrExpiredToken {
+					fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+				}
+				os.Exit(1)
+			}
+			for _, v := range variables {
+				description := ""
+				if v.Description != nil {
+					description = *v.Description
+				}
+				values[v.Key] = examplePlaceholder(v.Type, v.AllowedValues)
+				comments[v.Key] = exampleComment(description, v.Type, v.AllowedValues)
+			}
+		}
+
+		if len(values) == 0 {
+			fmt.Fprintln(s.Stdout, "No keys to write")
+			return nil
+		}
+
+		if _, err := os.Stat(exampleFile); err == nil {
+			fmt.Fprintf(s.Stderr, "Warning: File '%s' already exists\n", exampleFile)
+			confirmed, err := prompts.Confirm("Overwrite existing file?")
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if !confirmed {
+				fmt.Fprintln(s.Stdout, "Cancelled")
+				return nil
+			}
+		}
+
+		if err := utils.WriteEnvFileWithComments(exampleFile, values, comments); err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to write file '%s': %v\n", exampleFile, err)
+			os.Exit(1)
+		}
+
+		fmt.Fprintf(s.Stdout, "Wrote %d key(s) to %s\n", len(values), exampleFile)
+		return nil
+	},
+}
+
+// examplePlaceholder returns a harmless value of the given variable type for
+// .env.example files. Strings and secrets are left empty.
+func examplePlaceholder(variableType string, allowedValues []string) string {
+	switch variableType {
+	case "number":
+		return "0"
+	case "boolean":
+		return "false"
+	case "url":
+		return "https://example.com"
+	case "json":
+		return "{}"
+	case "duration":
+		return "30s"
+	case "enum":
+		if len(allowedValues) > 0 {
+			return allowedValues[0]
+		}
+	}
+	return ""
+}
+
+// exampleComment describes a key for .env.example files.
+func exampleComment(description, variableType string, allowedValues []string) string {
+	var lines []string
+	if description != "" {
+		lines = append(lines, description)
+	}
+	switch {
+	case len(allowedValues) > 0:
+		lines = append(lines, "One of: "+strings.Join(allowedValues, ", "))
+	case variableType != "" && variableType != "string":
+		lines = append(lines, "Type: "+variableType)
+	}
+	return strings.Join(lines, "\n")
+}
+
+var pushVariablesCmd = &cli.Command{
+	Name:      "push",
+	ShortHelp: "Sync variables from a .env file to an environment",
+	Usage:     "envoy variables push [project_id] [environment_id] [flags]",
+	Flags: cli.FlagsFunc(func(f *flag.FlagSet) {
+		f.String("file", ".env", "Path to the .env file to push")
+		f.Bool("prune", false, "Remove remote variables that are not in the file")
+	}),
+	FlagOptions: []cli.FlagOption{
+		{Name: "file", Short: "f"},
+	},
+	Exec: func(ctx context.Context, s *cli.State) error {
+		pushFile := cli.GetFlag[string](s, "file")
+		prune := cli.GetFlag[bool](s, "prune")
+
+		client, err := controllers.RequireToken()
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			if err == shared.ErrNoToken {
+				fmt.Fprintln(s.Stdout, "Please login first using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		var projectID, environmentID string
+
+		if len(s.Args) == 2 {
+			projectID = s.Args[0]
+			environmentID = s.Args[1]
+		} else if len(s.Args) == 1 {
+			fmt.Fprintln(s.Stderr, "Error: Both project_id and environment_id are required")
+			fmt.Fprintln(s.Stderr, "Usage: envoy variables push <project_id> <environment_id>")
+			os.Exit(1)
+		} else {
+			projectID, err = prompts.PromptForProject(client)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			environmentID, err = prompts.PromptForEnvironment(client, projectID)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		if _, err := os.Stat(pushFile); os.IsNotExist(err) {
+			fmt.Fprintf(s.Stderr, "Warning: File '%s' not found\n", pushFile)
+			os.Exit(1)
+		}
+
+		variables, err := utils.ParseEnvFile(pushFile)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to parse file '%s': %v\n", pushFile, err)
+			os.Exit(1)
+		}
+		skipped := skipEmptyValues(s.Stderr, variables)
+
+		diff, err := client.ReplaceEnvironmentVariables(projectID, environmentID, variables, true)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to compare variables: %v\n", err)
+			if err == shared.ErrExpiredToken {
+				fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		for _, key := range diff.Added {
+			fmt.Fprintf(s.Stdout, "  + %s\n", key)
+		}
+		for _, key := range diff.Changed {
+			fmt.Fprintf(s.Stdout, "  ~ %s\n", key)
+		}
+		// Keys left empty in the file are kept rather than pruned, since the
+		// file still lists them.
+		var removed []string
+		for _, key := range diff.Removed {
+			switch {
+			case slices.Contains(skipped, key):
+				fmt.Fprintf(s.Stdout, "    %s (empty in file, kept)\n", key)
+			case prune:
+				fmt.Fprintf(s.Stdout, "  - %s\n", key)
+				removed = append(removed, key)
+			default:
+				fmt.Fprintf(s.Stdout, "    %s (only in remote, kept; use --prune to remove)\n", key)
+			}
+		}
+
+		changes := len(diff.Added) + len(diff.Changed) + len(removed)
+		if changes == 0 {
+			fmt.Fprintln(s.Stdout, "Environment is already up to date")
+			return nil
+		}
+
+		fmt.Fprintf(s.Stdout, "\n%d to add, %d to change", len(diff.Added), len(diff.Changed))
+		if prune {
+			fmt.Fprintf(s.Stdout, ", %d to remove", len(removed))
+		}
+		fmt.Fprintf(s.Stdout, ", %d unchanged\n\n", diff.Unchanged)
+
+		confirmed, err := prompts.Confirm("Apply these changes?")
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if !confirmed {
+			fmt.Fprintln(s.Stdout, "Push cancelled")
+			return nil
+		}
+
+		if prune && len(skipped) == 0 {
+			_, err = client.ReplaceEnvironmentVariables(projectID, environmentID, variables, false)
+		} else {
+			operations := make([]controllers.VariableOperation, 0, changes)
+			for _, key := range append(diff.Added, diff.Changed...) {
+				operations = append(operations, controllers.VariableOperation{Op: "set", Key: key, Value: variables[key]})
+			}
+			for _, key := range removed {
+				operations = append(operations, controllers.VariableOperation{Op: "delete", Key: key})
+			}
+			_, err = client.BatchEnvironmentVariables(projectID, environmentID, operations)
+		}
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Failed to push variables: %v\n", err)
+			fmt.Fprintln(s.Stderr, "No variables were changed")
+			if err == shared.ErrExpiredToken {
+				fmt.Fprintln(s.Stdout, "Your session has expired. Please login again using 'envoy login'")
+			}
+			os.Exit(1)
+		}
+
+		fmt.Fprintf(s.Stdout, "Pushed %s to the environment\n", pushFile)
 		return nil
 	},
 }
@@ -323,7 +661,38 @@ var createVariableCmd = &cli.Command{
 	Name:      "create",
 	ShortHelp: "Create a new variable",
 	Usage:     "envoy variables create [project_id] [environment_id] [flags]",
+	Flags: cli.FlagsFunc(func(f *flag.FlagSet) {
+		f.String("type", "", "Variable type: string, secret, number, boolean, url, json, enum or duration (default string, or secret with --generate)")
+		f.String("allowed", "", "Comma-separated allowed values for enum variables")
+		f.String("generate", "", "Generate the value on the server: hex:N, base64:N, alphanumeric:N, uuid, password:N[:luds], rsa[:bits] or ed25519")
+		f.String("rotate-after", "", "Require the value to be rotated after this long, e.g. 90d")
+		f.String("expires", "", "Date the value expires, e.g. 2027-01-31")
+		f.String("label", "", "Comma-separated labels, e.g. team=payments,component=db")
+	}),
+	FlagOptions: []cli.FlagOption{
+		{Name: "type", Short: "t"},
+		{Name: "generate", Short: "g"},
+	},
 	Exec: func(ctx context.Context, s *cli.State) error {
+		variableType := cli.GetFlag[string](s, "type")
+		generate := cli.GetFlag[string](s, "generate")
+		rotation, err := parseRotationFlags(s)
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		labels, err := parseLabels(cli.GetFlag[string](s, "label"))
+		if err != nil {
+			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		var allowedValues []string
+		for _, v := range strings.Split(cli.GetFlag[string](s, "allowed"), ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				allowedValues = append(allowedValues, v)
+			}
+		}
+
 		client, err := controllers.RequireToken()
 		if err != nil {
 			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
@@ -367,13 +736,22 @@ var createVariableCmd = &cli.Command{
 				os.Exit(1)
 			}
 
-			value, err := prompts.PromptString("Variable value", true)
+			var value string
+			if generate == "" {
+				value, err = promptVariableValue(variableType)
+				if err != nil {
+					fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+			}
+
+			description, err := prompts.PromptString("Description (optional)", false)
 			if err != nil {
 				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
 				os.Exit(1)
 			}
 
-			variable, err := client.CreateEnvironmentVariable(projectID, environmentID, key, value)
+			variable, err := client.CreateEnvironmentVariable(projectID, environmentID, key, value, description, variableType, allowedValues, generate, rotation, labels)
 			if err != nil {
 				fmt.Fprintf(s.Stderr, "Failed to create variable: %v\n", err)
 				if err == shared.ErrExpiredToken {
@@ -385,7 +763,16 @@ var createVariableCmd = &cli.Command{
 			fmt.Fprintln(s.Stdout, "Variable created successfully!")
 			fmt.Fprintf(s.Stdout, "  ID: %s\n", variable.ID)
 			fmt.Fprintf(s.Stdout, "  Key: %s\n", variable.Key)
-			fmt.Fprintf(s.Stdout, "  Value: %s\n", variable.Value)
+			if variable.Description != nil && *variable.Description != "" {
+				fmt.Fprintf(s.Stdout, "  Description: %s\n", *variable.Description)
+			}
+			fmt.Fprintf(s.Stdout, "  Type: %s\n", variable.TypeLabel())
+			if generate != "" {
+				fmt.Fprintln(s.Stdout, "  Value: generated on the server (view it with 'envoy variables get --reveal')")
+			} else {
+				fmt.Fprintf(s.Stdout, "  Value: %s\n", variable.DisplayValue())
+			}
+			printLabels(s.Stdout, *variable)
 		} else if len(s.Args) == 1 {
 			fmt.Fprintln(s.Stderr, "Error: Both project_id and environment_id are required")
 			fmt.Fprintln(s.Stderr, "Usage: envoy variables create <project_id> <environment_id>")
@@ -409,13 +796,22 @@ var createVariableCmd = &cli.Command{
 				os.Exit(1)
 			}
 
-			value, err := prompts.PromptString("Variable value", true)
+			var value string
+			if generate == "" {
+				value, err = promptVariableValue(variableType)
+				if err != nil {
+					fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+			}
+
+			description, err := prompts.PromptString("Description (optional)", false)
 			if err != nil {
 				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
 				os.Exit(1)
 			}
 
-			variable, err := client.CreateEnvironmentVariable(projectID, environmentID, key, value)
+			variable, err := client.CreateEnvironmentVariable(projectID, environmentID, key, value, description, variableType, allowedValues, generate, rotation, labels)
 			if err != nil {
 				fmt.Fprintf(s.Stderr, "Failed to create variable: %v\n", err)
 				if err == shared.ErrExpiredToken {
@@ -427,17 +823,60 @@ var createVariableCmd = &cli.Command{
 			fmt.Fprintln(s.Stdout, "Variable created successfully!")
 			fmt.Fprintf(s.Stdout, "  ID: %s\n", variable.ID)
 			fmt.Fprintf(s.Stdout, "  Key: %s\n", variable.Key)
-			fmt.Fprintf(s.Stdout, "  Value: %s\n", variable.Value)
+			if variable.Description != nil && *variable.Description != "" {
+				fmt.Fprintf(s.Stdout, "  Description: %s\n", *variable.Description)
+			}
+			fmt.Fprintf(s.Stdout, "  Type: %s\n", variable.TypeLabel())
+			if generate != "" {
+				fmt.Fprintln(s.Stdout, "  Value: generated on the server (view it with 'envoy variables get --reveal')")
+			} else {
+				fmt.Fprintf(s.Stdout, "  Value: %s\n", variable.DisplayValue())
+			}
+			printLabels(s.Stdout, *variable)
 		}
 		return nil
 	},
 }
 
+// skipEmptyValues removes keys with empty values from variables, which the
+// server cannot store, warning about each one. It returns the removed keys.
+func skipEmptyValues(w io.Writer, variables map[string]string) []string {
+	var skipped []string
+	for key, value := range variables {
+		if value == "" {
+			skipped = append(skipped, key)
+			delete(variables, key)
+		}
+	}
+	sort.Strings(skipped)
+	for _, key := range skipped {
+		fmt.Fprintf(w, "Warning: skipping %s, empty values are not supported\n", key)
+	}
+	return skipped
+}
+
+// promptVariableValue asks for a variable value, hiding the input for secrets.
+func promptVariableValue(variableType string) (string, error) {
+	if variableType == "secret" {
+		return prompts.PromptPassword("Variable value")
+	}
+	return prompts.PromptString("Variable value", true)
+}
+
 var listVariablesCmd = &cli.Command{
 	Name:      "list",
 	ShortHelp: "List variables",
 	Usage:     "envoy variables list [project_id] [environment_id] [flags]",
+	Flags: cli.FlagsFunc(func(f *flag.FlagSet) {
+		f.Bool("reveal", false, "Show plaintext values (recorded in the project audit log)")
+		f.Bool("raw", false, "With --reveal, show ${...} references without expanding them")
+		f.String("label", "", "Only list variables with these labels, e.g. team=payments,component=db")
+	}),
 	Exec: func(ctx context.Context, s *cli.State) error {
+		reveal := cli.GetFlag[bool](s, "reveal")
+		raw := cli.GetFlag[bool](s, "raw")
+		labels := splitList(cli.GetFlag[string](s, "label"))
+
 		client, err := controllers.RequireToken()
 		if err != nil {
 			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
@@ -471,7 +910,13 @@ var listVariablesCmd = &cli.Command{
 				os.Exit(1)
 			}
 
-			variables, err := client.ListEnvironmentVariables(projectID, environmentID)
+			listVariables := client.ListEnvironmentVariables
+			if reveal {
+				listVariables = func(projectID, environmentID string, labels []string) ([]controllers.EnvironmentVariableResponse, error) {
+					return client.RevealEnvironmentVariables(projectID, environmentID, raw, labels)
+				}
+			}
+			variables, err := listVariables(projectID, environmentID, labels)
 			if err != nil {
 				fmt.Fprintf(s.Stderr, "Failed to list variables: %v\n", err)
 				if err == shared.ErrExpiredToken {
@@ -489,7 +934,24 @@ var listVariablesCmd = &cli.Command{
 			for _, v := range variables {
 				fmt.Fprintf(s.Stdout, "  ID: %s\n", v.ID)
 				fmt.Fprintf(s.Stdout, "  Key: %s\n", v.Key)
-				fmt.Fprintf(s.Stdout, "  Value: %s\n", v.Value)
+				if v.Description != nil && *v.Description != "" {
+					fmt.Fprintf(s.Stdout, "  Description: %s\n", *v.Description)
+				}
+				fmt.Fprintf(s.Stdout, "  Type: %s\n", v.TypeLabel())
+				if v.IsFile() {
+					fmt.Fprintf(s.Stdout, "  File: %s (mode %s)\n", v.Filename, v.Mode)
+				}
+				fmt.Fprintf(s.Stdout, "  Value: %s\n", v.DisplayValue())
+				if v.InheritedFrom != nil {
+					fmt.Fprintf(s.Stdout, "  Inherited from: %s\n", v.InheritedFrom.Name)
+				} else if v.Origin == "project" {
+					fmt.Fprintln(s.Stdout, "  Inherited from: project")
+				}
+				if v.ResolutionError != "" {
+					fmt.Fprintf(s.Stdout, "  Warning: %s\n", v.ResolutionError)
+				}
+				printLabels(s.Stdout, v)
+				printRotation(s.Stdout, v)
 				fmt.Fprintf(s.Stdout, "  Updated: %s\n", v.UpdatedAt)
 				fmt.Fprintln(s.Stdout, "")
 			}
@@ -510,7 +972,13 @@ var listVariablesCmd = &cli.Command{
 				os.Exit(1)
 			}
 
-			variables, err := client.ListEnvironmentVariables(projectID, environmentID)
+			listVariables := client.ListEnvironmentVariables
+			if reveal {
+				listVariables = func(projectID, environmentID string, labels []string) ([]controllers.EnvironmentVariableResponse, error) {
+					return client.RevealEnvironmentVariables(projectID, environmentID, raw, labels)
+				}
+			}
+			variables, err := listVariables(projectID, environmentID, labels)
 			if err != nil {
 				fmt.Fprintf(s.Stderr, "Failed to list variables: %v\n", err)
 				if err == shared.ErrExpiredToken {
@@ -528,7 +996,24 @@ var listVariablesCmd = &cli.Command{
 			for _, v := range variables {
 				fmt.Fprintf(s.Stdout, "  ID: %s\n", v.ID)
 				fmt.Fprintf(s.Stdout, "  Key: %s\n", v.Key)
-				fmt.Fprintf(s.Stdout, "  Value: %s\n", v.Value)
+				if v.Description != nil && *v.Description != "" {
+					fmt.Fprintf(s.Stdout, "  Description: %s\n", *v.Description)
+				}
+				fmt.Fprintf(s.Stdout, "  Type: %s\n", v.TypeLabel())
+				if v.IsFile() {
+					fmt.Fprintf(s.Stdout, "  File: %s (mode %s)\n", v.Filename, v.Mode)
+				}
+				fmt.Fprintf(s.Stdout, "  Value: %s\n", v.DisplayValue())
+				if v.InheritedFrom != nil {
+					fmt.Fprintf(s.Stdout, "  Inherited from: %s\n", v.InheritedFrom.Name)
+				} else if v.Origin == "project" {
+					fmt.Fprintln(s.Stdout, "  Inherited from: project")
+				}
+				if v.ResolutionError != "" {
+					fmt.Fprintf(s.Stdout, "  Warning: %s\n", v.ResolutionError)
+				}
+				printLabels(s.Stdout, v)
+				printRotation(s.Stdout, v)
 				fmt.Fprintf(s.Stdout, "  Updated: %s\n", v.UpdatedAt)
 				fmt.Fprintln(s.Stdout, "")
 			}
@@ -541,7 +1026,14 @@ var getVariableCmd = &cli.Command{
 	Name:      "get",
 	ShortHelp: "Get variable details",
 	Usage:     "envoy variables get [variable_id] [project_id] [environment_id] [flags]",
+	Flags: cli.FlagsFunc(func(f *flag.FlagSet) {
+		f.Bool("reveal", false, "Show the plaintext value (recorded in the project audit log)")
+		f.Bool("raw", false, "With --reveal, show ${...} references without expanding them")
+	}),
 	Exec: func(ctx context.Context, s *cli.State) error {
+		reveal := cli.GetFlag[bool](s, "reveal")
+		raw := cli.GetFlag[bool](s, "raw")
+
 		client, err := controllers.RequireToken()
 		if err != nil {
 			fmt.Fprintf(s.Stderr, "Error: %v\n", err)
@@ -576,7 +1068,13 @@ var getVariableCmd = &cli.Command{
 				os.Exit(1)
 			}
 
-			variable, err := client.GetEnvironmentVariable(projectID, environmentID, variableID)
+			getVariable := client.GetEnvironmentVariable
+			if reveal {
+				getVariable = func(projectID, environmentID, variableID string) (*controllers.EnvironmentVariableResponse, error) {
+					return client.RevealEnvironmentVariable(projectID, environmentID, variableID, raw)
+				}
+			}
+			variable, err := getVariable(projectID, environmentID, variableID)
 			if err != nil {
 				fmt.Fprintf(s.Stderr, "Failed to get variable: %v\n", err)
 				if err == shared.ErrExpiredToken {
@@ -588,7 +1086,19 @@ var getVariableCmd = &cli.Command{
 			fmt.Fprintln(s.Stdout, "Variable Details:")
 			fmt.Fprintf(s.Stdout, "  ID: %s\n", variable.ID)
 			fmt.Fprintf(s.Stdout, "  Key: %s\n", variable.Key)
-			fmt.Fprintf(s.Stdout, "  Value: %s\n", variable.Value)
+			if variable.Description != nil && *variable.Description != "" {
+				fmt.Fprintf(s.Stdout, "  Description: %s\n", *variable.Description)
+			}
+			fmt.Fprintf(s.Stdout, "  Type: %s\n", variable.TypeLabel())
+			if variable.IsFile() {
+				fmt.Fprintf(s.Stdout, "  File: %s (mode %s)\n", variable.Filename, variable.Mode)
+			}
+			fmt.Fprintf(s.Stdout, "  Value: %s\n", variable.DisplayValue())
+			if variable.ResolutionError != "" {
+				fmt.Fprintf(s.Stdout, "  Warning: %s\n", variable.ResolutionError)
+			}
+			printLabels(s.Stdout, *variable)
+			printRotation(s.Stdout, *variable)
 			fmt.Fprintf(s.Stdout, "  Environment ID: %s\n", variable.EnvironmentID)
 			fmt.Fprintf(s.Stdout, "  Created: %s\n", variable.CreatedAt)
 			fmt.Fprintf(s.Stdout, "  Updated: %s\n", variable.UpdatedAt)
@@ -615,7 +1125,13 @@ var getVariableCmd = &cli.Command{
 				os.Exit(1)
 			}
 
-			variable, err := client.GetEnvironmentVariable(projectID, environmentID, variableID)
+			getVariable := client.GetEnvironmentVariable
+			if reveal {
+				getVariable = func(projectID, environmentID, variableID string) (*controllers.EnvironmentVariableResponse, error) {
+					return client.RevealEnvironmentVariable(projectID, environmentID, variableID, raw)
+				}
+			}
+			variable, err := getVariable(projectID, environmentID, variableID)
 			if err != nil {
 				fmt.Fprintf(s.Stderr, "Failed to get variable: %v\n", err)
 				if err == shared.ErrExpiredToken {
@@ -627,7 +1143,19 @@ var getVariableCmd = &cli.Command{
 			fmt.Fprintln(s.Stdout, "Variable Details:")
 			fmt.Fprintf(s.Stdout, "  ID: %s\n", variable.ID)
 			fmt.Fprintf(s.Stdout, "  Key: %s\n", variable.Key)
-			fmt.Fprintf(s.Stdout, "  Value: %s\n", variable.Value)
+			if variable.Description != nil && *variable.Description != "" {
+				fmt.Fprintf(s.Stdout, "  Description: %s\n", *variable.Description)
+			}
+			fmt.Fprintf(s.Stdout, "  Type: %s\n", variable.TypeLabel())
+			if variable.IsFile() {
+				fmt.Fprintf(s.Stdout, "  File: %s (mode %s)\n", variable.Filename, variable.Mode)
+			}
+			fmt.Fprintf(s.Stdout, "  Value: %s\n", variable.DisplayValue())
+			if variable.ResolutionError != "" {
+				fmt.Fprintf(s.Stdout, "  Warning: %s\n", variable.ResolutionError)
+			}
+			printLabels(s.Stdout, *variable)
+			printRotation(s.Stdout, *variable)
 			fmt.Fprintf(s.Stdout, "  Environment ID: %s\n", variable.EnvironmentID)
 			fmt.Fprintf(s.Stdout, "  Created: %s\n", variable.CreatedAt)
 			fmt.Fprintf(s.Stdout, "  Updated: %s\n", variable.UpdatedAt)
@@ -690,13 +1218,22 @@ var updateVariableCmd = &cli.Command{
 				os.Exit(1)
 			}
 
-			value, err := prompts.PromptString("Variable value", true)
+			value, err := promptVariableValue(variable.Type)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			description, err := prompts.PromptString("Description (leave empty to keep current)", false)
 			if err != nil {
 				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
 				os.Exit(1)
 			}
+			if description == "" && variable.Description != nil {
+				description = *variable.Description
+			}
 
-			updatedVariable, err := client.UpdateEnvironmentVariable(projectID, environmentID, variableID, key, value)
+			updatedVariable, err := client.UpdateEnvironmentVariable(projectID, environmentID, variableID, key, value, description)
 			if err != nil {
 				fmt.Fprintf(s.Stderr, "Failed to update variable: %v\n", err)
 				if err == shared.ErrExpiredToken {
@@ -707,7 +1244,10 @@ var updateVariableCmd = &cli.Command{
 
 			fmt.Fprintln(s.Stdout, "Variable updated successfully!")
 			fmt.Fprintf(s.Stdout, "  Key: %s\n", updatedVariable.Key)
-			fmt.Fprintf(s.Stdout, "  Value: %s\n", updatedVariable.Value)
+			if updatedVariable.Description != nil && *updatedVariable.Description != "" {
+				fmt.Fprintf(s.Stdout, "  Description: %s\n", *updatedVariable.Description)
+			}
+			fmt.Fprintf(s.Stdout, "  Value: %s\n", updatedVariable.DisplayValue())
 		} else if len(s.Args) >= 1 && len(s.Args) < 3 {
 			fmt.Fprintln(s.Stderr, "Error: All three arguments are required: variable_id, project_id, and environment_id")
 			fmt.Fprintln(s.Stderr, "Usage: envoy variables update <variable_id> <project_id> <environment_id>")
@@ -746,13 +1286,22 @@ var updateVariableCmd = &cli.Command{
 				os.Exit(1)
 			}
 
-			value, err := prompts.PromptString("Variable value", true)
+			value, err := promptVariableValue(variable.Type)
+			if err != nil {
+				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			description, err := prompts.PromptString("Description (leave empty to keep current)", false)
 			if err != nil {
 				fmt.Fprintf(s.Stderr, "Error: %v\n", err)
 				os.Exit(1)
 			}
+			if description == "" && variable.Description != nil {
+				description = *variable.Description
+			}
 
-			updatedVariable, err := client.UpdateEnvironmentVariable(projectID, environmentID, variableID, key, value)
+			updatedVariable, err := client.UpdateEnvironmentVariable(projectID, environmentID, variableID, key, value, description)
 			if err != nil {
 				fmt.Fprintf(s.Stderr, "Failed to update variable: %v\n", err)
 				if err == shared.ErrExpiredToken {
@@ -763,7 +1312,10 @@ var updateVariableCmd = &cli.Command{
 
 			fmt.Fprintln(s.Stdout, "Variable updated successfully!")
 			fmt.Fprintf(s.Stdout, "  Key: %s\n", updatedVariable.Key)
-			fmt.Fprintf(s.Stdout, "  Value: %s\n", updatedVariable.Value)
+			if updatedVariable.Description != nil && *updatedVariable.Description != "" {
+				fmt.Fprintf(s.Stdout, "  Description: %s\n", *updatedVariable.Description)
+			}
+			fmt.Fprintf(s.Stdout, "  Value: %s\n", updatedVariable.DisplayValue())
 		}
 		return nil
 	},
@@ -10,36 +10,10 @@ import (
 	"database/sql"
 )
 
-const canUserModifyEnvironmentVariable = `-- name: CanUserModifyEnvironmentVariable :one
-SELECT COUNT(*) as count
-FROM environment_variables ev
-INNER JOIN environments e ON ev.environment_id = e.id
-INNER JOIN projects p ON e.project_id = p.id
-LEFT JOIN project_users pu ON p.id = pu.project_id
-WHERE ev.id = ? AND e.deleted_at IS NULL AND p.deleted_at IS NULL
-AND (
-    p.owner_id = ? OR 
-    (pu.user_id = ? AND pu.role = 'editor')
-)
-`
-
-type CanUserModifyEnvironmentVariableParams struct {
-	ID      string
-	OwnerID string
-	UserID  string
-}
-
-func (q *Queries) CanUserModifyEnvironmentVariable(ctx context.Context, arg CanUserModifyEnvironmentVariableParams) (int64, error) {
-	row := q.db.QueryRowContext(ctx, canUserModifyEnvironmentVariable, arg.ID, arg.OwnerID, arg.UserID)
-	var count int64
-	err := row.Scan(&count)
-	return count, err
-}
-
 const createEnvironmentVariable = `-- name: CreateEnvironmentVariable :one
-INSERT INTO environment_variables (id, environment_id, key, value, description, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, ?)
-RETURNING id, environment_id, key, value, description, created_at, updated_at
+INSERT INTO environment_variables (id, environment_id, key, value, description, created_at, updated_at, type, allowed_values, rotate_after, expires_at, rotated_at, filename, file_mode, labels)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, environment_id, key, value, description, created_at, updated_at, type, allowed_values, rotate_after, expires_at, rotated_at, filename, file_mode, labels
 `
 
 type CreateEnvironmentVariableParams struct {
@@ -50,6 +24,14 @@ type CreateEnvironmentVariableParams struct {
 	Description   sql.NullString
 	CreatedAt     sql.NullTime
 	UpdatedAt     sql.NullTime
+	Type          string
+	AllowedValues sql.NullString
+	RotateAfter   sql.NullString
+	ExpiresAt     sql.NullTime
+	RotatedAt     sql.NullTime
+	Filename      sql.NullString
+	FileMode      sql.NullString
+	Labels        sql.NullString
 }
 
 func (q *Queries) CreateEnvironmentVariable(ctx context.Context, arg CreateEnvironmentVariableParams) (EnvironmentVariable, error) {
@@ -61,6 +43,14 @@ func (q *Queries) CreateEnvironmentVariable(ctx context.Context, arg CreateEnvir
 		arg.Description,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.Type,
+		arg.AllowedValues,
+		arg.RotateAfter,
+		arg.ExpiresAt,
+		arg.RotatedAt,
+		arg.Filename,
+		arg.FileMode,
+		arg.Labels,
 	)
 	var i EnvironmentVariable
 	err := row.Scan(
@@ -71,6 +61,14 @@ func (q *Queries) CreateEnvironmentVariable(ctx context.Context, arg CreateEnvir
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Type,
+		&i.AllowedValues,
+		&i.RotateAfter,
+		&i.ExpiresAt,
+		&i.RotatedAt,
+		&i.Filename,
+		&i.FileMode,
+		&i.Labels,
 	)
 	return i, err
 }
@@ -85,29 +83,32 @@ func (q *Queries) DeleteEnvironmentVariable(ctx context.Context, id string) erro
 	return err
 }
 
-const getAccessibleEnvironmentVariable = `-- name: GetAccessibleEnvironmentVariable :one
-SELECT ev.id, ev.environment_id, ev.key, ev.value, ev.description, ev.created_at, ev.updated_at
-FROM environment_variables ev
-INNER JOIN environments e ON ev.environment_id = e.id
-WHERE ev.id = ? AND e.deleted_at IS NULL
-AND (EXISTS (
-    SELECT 1 FROM projects p 
-    WHERE p.id = e.project_id AND p.deleted_at IS NULL
-    AND (p.owner_id = ? OR EXISTS (
-        SELECT 1 FROM project_users pu 
-        WHERE pu.project_id = p.id AND pu.user_id = ?
-    ))
-))
+const deleteEnvironmentVariableByKey = `-- name: DeleteEnvironmentVariableByKey :execrows
+DELETE FROM environment_variables
+WHERE environment_id = ? AND key = ?
 `
 
-type GetAccessibleEnvironmentVariableParams struct {
-	ID      string
-	OwnerID string
-	UserID  string
+type DeleteEnvironmentVariableByKeyParams struct {
+	EnvironmentID string
+	Key           string
 }
 
-func (q *Queries) GetAccessibleEnvironmentVariable(ctx context.Context, arg GetAccessibleEnvironmentVariableParams) (EnvironmentVariable, error) {
-	row := q.db.QueryRowContext(ctx, getAccessibleEnvironmentVariable, arg.ID, arg.OwnerID, arg.UserID)
+func (q *Queries) DeleteEnvironmentVariableByKey(ctx context.Context, arg DeleteEnvironmentVariableByKeyParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteEnvironmentVariableByKey, arg.EnvironmentID, arg.Key)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getEnvironmentVariable = `-- name: GetEnvironmentVariable :one
+SELECT id, environment_id, key, value, description, created_at, updated_at, type, allowed_values, rotate_after, expires_at, rotated_at, filename, file_mode, labels
+FROM environment_variables
+WHERE id = ?
+`
+
+func (q *Queries) GetEnvironmentVariable(ctx context.Context, id string) (EnvironmentVariable, error) {
+	row := q.db.QueryRowContext(ctx, getEnvironmentVariable, id)
 	var i EnvironmentVariable
 	err := row.Scan(
 		&i.ID,
@@ -117,18 +118,31 @@ func (q *Queries) GetAccessibleEnvironmentVariable(ctx context.Context, arg GetA
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Type,
+		&i.AllowedValues,
+		&i.RotateAfter,
+		&i.ExpiresAt,
+		&i.RotatedAt,
+		&i.Filename,
+		&i.FileMode,
+		&i.Labels,
 	)
 	return i, err
 }
 
-const getEnvironmentVariable = `-- name: GetEnvironmentVariable :one
-SELECT id, environment_id, key, value, description, created_at, updated_at
+const getEnvironmentVariableByKey = `-- name: GetEnvironmentVariableByKey :one
+SELECT id, environment_id, key, value, description, created_at, updated_at, type, allowed_values, rotate_after, expires_at, rotated_at, filename, file_mode, labels
 FROM environment_variables
-WHERE id = ?
+WHERE environment_id = ? AND key = ?
 `
 
-func (q *Queries) GetEnvironmentVariable(ctx context.Context, id string) (EnvironmentVariable, error) {
-	row := q.db.QueryRowContext(ctx, getEnvironmentVariable, id)
+type GetEnvironmentVariableByKeyParams struct {
+	EnvironmentID string
+	Key           string
+}
+
+func (q *Queries) GetEnvironmentVariableByKey(ctx context.Context, arg GetEnvironmentVariableByKeyParams) (EnvironmentVariable, error) {
+	row := q.db.QueryRowContext(ctx, getEnvironmentVariableByKey, arg.EnvironmentID, arg.Key)
 	var i EnvironmentVariable
 	err := row.Scan(
 		&i.ID,
@@ -138,12 +152,20 @@ func (q *Queries) GetEnvironmentVariable(ctx context.Context, id string) (Enviro
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Type,
+		&i.AllowedValues,
+		&i.RotateAfter,
+		&i.ExpiresAt,
+		&i.RotatedAt,
+		&i.Filename,
+		&i.FileMode,
+		&i.Labels,
 	)
 	return i, err
 }
 
 const listEnvironmentVariablesByEnvironment = `-- name: ListEnvironmentVariablesByEnvironment :many
-SELECT id, environment_id, key, value, description, created_at, updated_at
+SELECT id, environment_id, key, value, description, created_at, updated_at, type, allowed_values, rotate_after, expires_at, rotated_at, filename, file_mode, labels
 FROM environment_variables
 WHERE environment_id = ?
 ORDER BY created_at DESC
@@ -166,6 +188,152 @@ func (q *Queries) ListEnvironmentVariablesByEnvironment(ctx context.Context, env
 			&i.Description,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Type,
+			&i.AllowedValues,
+			&i.RotateAfter,
+			&i.ExpiresAt,
+			&i.RotatedAt,
+			&i.Filename,
+			&i.FileMode,
+			&i.Labels,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserEnvironmentVariables = `-- name: ListUserEnvironmentVariables :many
+SELECT ev.id, ev.key, ev.value, ev.type,
+    e.id AS environment_id, e.name AS environment_name, p.id AS project_id, p.name AS project_name,
+    p.owner_id, pu.role AS member_role, pu.elevated_until
+FROM environment_variables ev
+INNER JOIN environments e ON ev.environment_id = e.id
+INNER JOIN projects p ON e.project_id = p.id
+LEFT JOIN project_users pu ON pu.project_id = p.id AND pu.user_id = ?
+    AND (pu.expires_at IS NULL OR pu.expires_at > ?)
+WHERE e.deleted_at IS NULL AND p.deleted_at IS NULL
+AND (p.owner_id = ? OR pu.id IS NOT NULL)
+ORDER BY p.name, e.name, ev.key
+`
+
+type ListUserEnvironmentVariablesParams struct {
+	UserID string
+	Now    sql.NullTime
+}
+
+type ListUserEnvironmentVariablesRow struct {
+	ID              string
+	Key             string
+	Value           string
+	Type            string
+	EnvironmentID   string
+	EnvironmentName string
+	ProjectID       string
+	ProjectName     string
+	OwnerID         string
+	MemberRole      sql.NullString
+	ElevatedUntil   sql.NullTime
+}
+
+func (q *Queries) ListUserEnvironmentVariables(ctx context.Context, arg ListUserEnvironmentVariablesParams) ([]ListUserEnvironmentVariablesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUserEnvironmentVariables, arg.UserID, arg.Now, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUserEnvironmentVariablesRow
+	for rows.Next() {
+		var i ListUserEnvironmentVariablesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Key,
+			&i.Value,
+			&i.Type,
+			&i.EnvironmentID,
+			&i.EnvironmentName,
+			&i.ProjectID,
+			&i.ProjectName,
+			&i.OwnerID,
+			&i.MemberRole,
+			&i.ElevatedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserRotationVariables = `-- name: ListUserRotationVariables :many
+SELECT ev.id, ev.key, ev.type, ev.updated_at, ev.rotate_after, ev.expires_at, ev.rotated_at,
+    e.id AS environment_id, e.name AS environment_name, p.id AS project_id, p.name AS project_name
+FROM environment_variables ev
+INNER JOIN environments e ON ev.environment_id = e.id
+INNER JOIN projects p ON e.project_id = p.id
+WHERE (ev.rotate_after IS NOT NULL OR ev.expires_at IS NOT NULL)
+AND e.deleted_at IS NULL AND p.deleted_at IS NULL
+AND (p.owner_id = ? OR EXISTS (
+    SELECT 1 FROM project_users pu
+    WHERE pu.project_id = p.id AND pu.user_id = ?
+    AND (pu.expires_at IS NULL OR pu.expires_at > ?)
+))
+ORDER BY p.name, e.name, ev.key
+`
+
+type ListUserRotationVariablesParams struct {
+	UserID string
+	Now    sql.NullTime
+}
+
+type ListUserRotationVariablesRow struct {
+	ID              string
+	Key             string
+	Type            string
+	UpdatedAt       sql.NullTime
+	RotateAfter     sql.NullString
+	ExpiresAt       sql.NullTime
+	RotatedAt       sql.NullTime
+	EnvironmentID   string
+	EnvironmentName string
+	ProjectID       string
+	ProjectName     string
+}
+
+func (q *Queries) ListUserRotationVariables(ctx context.Context, arg ListUserRotationVariablesParams) ([]ListUserRotationVariablesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUserRotationVariables, arg.UserID, arg.UserID, arg.Now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUserRotationVariablesRow
+	for rows.Next() {
+		var i ListUserRotationVariablesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Key,
+			&i.Type,
+			&i.UpdatedAt,
+			&i.RotateAfter,
+			&i.ExpiresAt,
+			&i.RotatedAt,
+			&i.EnvironmentID,
+			&i.EnvironmentName,
+			&i.ProjectID,
+			&i.ProjectName,
 		); err != nil {
 			return nil, err
 		}
@@ -182,17 +350,24 @@ func (q *Queries) ListEnvironmentVariablesByEnvironment(ctx context.Context, env
 
 const updateEnvironmentVariable = `-- name: UpdateEnvironmentVariable :one
 UPDATE environment_variables
-SET key = ?, value = ?, description = ?, updated_at = ?
+SET key = ?, value = ?, description = ?, updated_at = ?, type = ?, allowed_values = ?, rotated_at = ?, expires_at = ?, filename = ?, file_mode = ?, labels = ?
 WHERE id = ?
-RETURNING id, environment_id, key, value, description, created_at, updated_at
+RETURNING id, environment_id, key, value, description, created_at, updated_at, type, allowed_values, rotate_after, expires_at, rotated_at, filename, file_mode, labels
 `
 
 type UpdateEnvironmentVariableParams struct {
-	Key         string
-	Value       string
-	Description sql.NullString
-	UpdatedAt   sql.NullTime
-	ID          string
+	Key           string
+	Value         string
+	Description   sql.NullString
+	UpdatedAt     sql.NullTime
+	Type          string
+	AllowedValues sql.NullString
+	RotatedAt     sql.NullTime
+	ExpiresAt     sql.NullTime
+	Filename      sql.NullString
+	FileMode      sql.NullString
+	Labels        sql.NullString
+	ID            string
 }
 
 func (q *Queries) UpdateEnvironmentVariable(ctx context.Context, arg UpdateEnvironmentVariableParams) (EnvironmentVariable, error) {
@@ -201,7 +376,159 @@ func (q *Queries) UpdateEnvironmentVariable(ctx context.Context, arg UpdateEnvir
 		arg.Value,
 		arg.Description,
 		arg.UpdatedAt,
+		arg.Type,
+		arg.AllowedValues,
+		arg.RotatedAt,
+		arg.ExpiresAt,
+		arg.Filename,
+		arg.FileMode,
+		arg.Labels,
+		arg.ID,
+	)
+	var i EnvironmentVariable
+	err := row.Scan(
+		&i.ID,
+		&i.EnvironmentID,
+		&i.Key,
+		&i.Value,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Type,
+		&i.AllowedValues,
+		&i.RotateAfter,
+		&i.ExpiresAt,
+		&i.RotatedAt,
+		&i.Filename,
+		&i.FileMode,
+		&i.Labels,
+	)
+	return i, err
+}
+
+const updateEnvironmentVariableLabels = `-- name: UpdateEnvironmentVariableLabels :one
+UPDATE environment_variables
+SET labels = ?, updated_at = ?
+WHERE id = ?
+RETURNING id, environment_id, key, value, description, created_at, updated_at, type, allowed_values, rotate_after, expires_at, rotated_at, filename, file_mode, labels
+`
+
+type UpdateEnvironmentVariableLabelsParams struct {
+	Labels    sql.NullString
+	UpdatedAt sql.NullTime
+	ID        string
+}
+
+func (q *Queries) UpdateEnvironmentVariableLabels(ctx context.Context, arg UpdateEnvironmentVariableLabelsParams) (EnvironmentVariable, error) {
+	row := q.db.QueryRowContext(ctx, updateEnvironmentVariableLabels, arg.Labels, arg.UpdatedAt, arg.ID)
+	var i EnvironmentVariable
+	err := row.Scan(
+		&i.ID,
+		&i.EnvironmentID,
+		&i.Key,
+		&i.Value,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Type,
+		&i.AllowedValues,
+		&i.RotateAfter,
+		&i.ExpiresAt,
+		&i.RotatedAt,
+		&i.Filename,
+		&i.FileMode,
+		&i.Labels,
+	)
+	return i, err
+}
+
+const updateEnvironmentVariableRotation = `-- name: UpdateEnvironmentVariableRotation :one
+UPDATE environment_variables
+SET rotate_after = ?, expires_at = ?, updated_at = ?
+WHERE id = ?
+RETURNING id, environment_id, key, value, description, created_at, updated_at, type, allowed_values, rotate_after, expires_at, rotated_at, filename, file_mode, labels
+`
+
+type UpdateEnvironmentVariableRotationParams struct {
+	RotateAfter sql.NullString
+	ExpiresAt   sql.NullTime
+	UpdatedAt   sql.NullTime
+	ID          string
+}
+
+func (q *Queries) UpdateEnvironmentVariableRotation(ctx context.Context, arg UpdateEnvironmentVariableRotationParams) (EnvironmentVariable, error) {
+	row := q.db.QueryRowContext(ctx, updateEnvironmentVariableRotation,
+		arg.RotateAfter,
+		arg.ExpiresAt,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	var i EnvironmentVariable
+	err := row.Scan(
+		&i.ID,
+		&i.EnvironmentID,
+		&i.Key,
+		&i.Value,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Type,
+		&i.AllowedValues,
+		&i.RotateAfter,
+		&i.ExpiresAt,
+		&i.RotatedAt,
+		&i.Filename,
+		&i.FileMode,
+		&i.Labels,
+	)
+	return i, err
+}
+
+const upsertEnvironmentVariable = `-- name: UpsertEnvironmentVariable :one
+INSERT INTO environment_variables (id, environment_id, key, value, description, created_at, updated_at, type, allowed_values, rotate_after, expires_at, rotated_at, filename, file_mode, labels)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (environment_id, key) DO UPDATE
+SET value = excluded.value, description = COALESCE(excluded.description, environment_variables.description), updated_at = excluded.updated_at,
+    rotated_at = CASE WHEN excluded.value = environment_variables.value THEN environment_variables.rotated_at ELSE excluded.rotated_at END,
+    expires_at = CASE WHEN excluded.value = environment_variables.value THEN environment_variables.expires_at ELSE NULL END
+RETURNING id, environment_id, key, value, description, created_at, updated_at, type, allowed_values, rotate_after, expires_at, rotated_at, filename, file_mode, labels
+`
+
+type UpsertEnvironmentVariableParams struct {
+	ID            string
+	EnvironmentID string
+	Key           string
+	Value         string
+	Description   sql.NullString
+	CreatedAt     sql.NullTime
+	UpdatedAt     sql.NullTime
+	Type          string
+	AllowedValues sql.NullString
+	RotateAfter   sql.NullString
+	ExpiresAt     sql.NullTime
+	RotatedAt     sql.NullTime
+	Filename      sql.NullString
+	FileMode      sql.NullString
+	Labels        sql.NullString
+}
+
+func (q *Queries) UpsertEnvironmentVariable(ctx context.Context, arg UpsertEnvironmentVariableParams) (EnvironmentVariable, error) {
+	row := q.db.QueryRowContext(ctx, upsertEnvironmentVariable,
 		arg.ID,
+		arg.EnvironmentID,
+		arg.Key,
+		arg.Value,
+		arg.Description,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.Type,
+		arg.AllowedValues,
+		arg.RotateAfter,
+		arg.ExpiresAt,
+		arg.RotatedAt,
+		arg.Filename,
+		arg.FileMode,
+		arg.Labels,
 	)
 	var i EnvironmentVariable
 	err := row.Scan(
@@ -212,6 +539,14 @@ func (q *Queries) UpdateEnvironmentVariable(ctx context.Context, arg UpdateEnvir
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Type,
+		&i.AllowedValues,
+		&i.RotateAfter,
+		&i.ExpiresAt,
+		&i.RotatedAt,
+		&i.Filename,
+		&i.FileMode,
+		&i.Labels,
 	)
 	return i, err
 }
@@ -10,35 +10,10 @@ import (
 	"database/sql"
 )
 
-const canUserModifyEnvironment = `-- name: CanUserModifyEnvironment :one
-SELECT COUNT(*) as count
-FROM environments e
-INNER JOIN projects p ON e.project_id = p.id
-LEFT JOIN project_users pu ON p.id = pu.project_id
-WHERE e.id = ? AND e.deleted_at IS NULL AND p.deleted_at IS NULL
-AND (
-    p.owner_id = ? OR 
-    (pu.user_id = ? AND pu.role = 'editor')
-)
-`
-
-type CanUserModifyEnvironmentParams struct {
-	ID      string
-	OwnerID string
-	UserID  string
-}
-
-func (q *Queries) CanUserModifyEnvironment(ctx context.Context, arg CanUserModifyEnvironmentParams) (int64, error) {
-	row := q.db.QueryRowContext(ctx, canUserModifyEnvironment, arg.ID, arg.OwnerID, arg.UserID)
-	var count int64
-	err := row.Scan(&count)
-	return count, err
-}
-
 const createEnvironment = `-- name: CreateEnvironment :one
-INSERT INTO environments (id, project_id, name, description, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?)
-RETURNING id, project_id, name, description, created_at, updated_at, deleted_at
+INSERT INTO environments (id, project_id, name, description, parent_id, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING id, project_id, name, description, created_at, updated_at, deleted_at, parent_id
 `
 
 type CreateEnvironmentParams struct {
@@ -46,6 +21,7 @@ type CreateEnvironmentParams struct {
 	ProjectID   string
 	Name        string
 	Description sql.NullString
+	ParentID    sql.NullString
 	CreatedAt   sql.NullTime
 	UpdatedAt   sql.NullTime
 }
@@ -56,6 +32,7 @@ func (q *Queries) CreateEnvironment(ctx context.Context, arg CreateEnvironmentPa
 		arg.ProjectID,
 		arg.Name,
 		arg.Description,
+		arg.ParentID,
 		arg.CreatedAt,
 		arg.UpdatedAt,
 	)
@@ -68,6 +45,7 @@ func (q *Queries) CreateEnvironment(ctx context.Context, arg CreateEnvironmentPa
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.ParentID,
 	)
 	return i, err
 }
@@ -88,43 +66,8 @@ func (q *Queries) DeleteEnvironment(ctx context.Context, arg DeleteEnvironmentPa
 	return err
 }
 
-const getAccessibleEnvironment = `-- name: GetAccessibleEnvironment :one
-SELECT e.id, e.project_id, e.name, e.description, e.created_at, e.updated_at, e.deleted_at
-FROM environments e
-WHERE e.id = ? AND e.deleted_at IS NULL
-AND (EXISTS (
-    SELECT 1 FROM projects p 
-    WHERE p.id = e.project_id AND p.deleted_at IS NULL
-    AND (p.owner_id = ? OR EXISTS (
-        SELECT 1 FROM project_users pu 
-        WHERE pu.project_id = p.id AND pu.user_id = ?
-    ))
-))
-`
-
-type GetAccessibleEnvironmentParams struct {
-	ID      string
-	OwnerID string
-	UserID  string
-}
-
-func (q *Queries) GetAccessibleEnvironment(ctx context.Context, arg GetAccessibleEnvironmentParams) (Environment, error) {
-	row := q.db.QueryRowContext(ctx, getAccessibleEnvironment, arg.ID, arg.OwnerID, arg.UserID)
-	var i Environment
-	err := row.Scan(
-		&i.ID,
-		&i.ProjectID,
-		&i.Name,
-		&i.Description,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.DeletedAt,
-	)
-	return i, err
-}
-
 const getEnvironment = `-- name: GetEnvironment :one
-SELECT id, project_id, name, description, created_at, updated_at, deleted_at
+SELECT id, project_id, name, description, created_at, updated_at, deleted_at, parent_id
 FROM environments
 WHERE id = ? AND deleted_at IS NULL
 `
@@ -140,12 +83,13 @@ func (q *Queries) GetEnvironment(ctx context.Context, id string) (Environment, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.ParentID,
 	)
 	return i, err
 }
 
 const listEnvironmentsByProject = `-- name: ListEnvironmentsByProject :many
-SELECT id, project_id, name, description, created_at, updated_at, deleted_at
+SELECT id, project_id, name, description, created_at, updated_at, deleted_at, parent_id
 FROM environments
 WHERE project_id = ? AND deleted_at IS NULL
 ORDER BY created_at DESC
@@ -168,6 +112,7 @@ func (q *Queries) ListEnvironmentsByProject(ctx context.Context, projectID strin
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.ParentID,
 		); err != nil {
 			return nil, err
 		}
@@ -184,14 +129,15 @@ func (q *Queries) ListEnvironmentsByProject(ctx context.Context, projectID strin
 
 const updateEnvironment = `-- name: UpdateEnvironment :one
 UPDATE environments
-SET name = ?, description = ?, updated_at = ?
+SET name = ?, description = ?, parent_id = ?, updated_at = ?
 WHERE id = ? AND deleted_at IS NULL
-RETURNING id, project_id, name, description, created_at, updated_at, deleted_at
+RETURNING id, project_id, name, description, created_at, updated_at, deleted_at, parent_id
 `
 
 type UpdateEnvironmentParams struct {
 	Name        string
 	Description sql.NullString
+	ParentID    sql.NullString
 	UpdatedAt   sql.NullTime
 	ID          string
 }
@@ -200,6 +146,7 @@ func (q *Queries) UpdateEnvironment(ctx context.Context, arg UpdateEnvironmentPa
 	row := q.db.QueryRowContext(ctx, updateEnvironment,
 		arg.Name,
 		arg.Description,
+		arg.ParentID,
 		arg.UpdatedAt,
 		arg.ID,
 	)
@@ -212,6 +159,7 @@ func (q *Queries) UpdateEnvironment(ctx context.Context, arg UpdateEnvironmentPa
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.ParentID,
 	)
 	return i, err
 }
@@ -8,6 +8,15 @@ import (
 	"database/sql"
 )
 
+type AuditLog struct {
+	ID        string
+	ProjectID sql.NullString
+	UserID    string
+	Action    string
+	Details   sql.NullString
+	CreatedAt sql.NullTime
+}
+
 type Environment struct {
 	ID          string
 	ProjectID   string
@@ -16,6 +25,7 @@ type Environment struct {
 	CreatedAt   sql.NullTime
 	UpdatedAt   sql.NullTime
 	DeletedAt   sql.NullTime
+	ParentID    sql.NullString
 }
 
 type EnvironmentVariable struct {
@@ -26,6 +36,14 @@ type EnvironmentVariable struct {
 	Description   sql.NullString
 	CreatedAt     sql.NullTime
 	UpdatedAt     sql.NullTime
+	Type          string
+	AllowedValues sql.NullString
+	RotateAfter   sql.NullString
+	ExpiresAt     sql.NullTime
+	RotatedAt     sql.NullTime
+	Filename      sql.NullString
+	FileMode      sql.NullString
+	Labels        sql.NullString
 }
 
 type Project struct {
@@ -39,21 +57,59 @@ type Project struct {
 	DeletedAt   sql.NullTime
 }
 
+type ProjectAccessRequest struct {
+	ID         string
+	ProjectID  string
+	UserID     string
+	Role       string
+	Message    sql.NullString
+	Status     string
+	ReviewedBy sql.NullString
+	ReviewedAt sql.NullTime
+	CreatedAt  sql.NullTime
+	UpdatedAt  sql.NullTime
+}
+
+type ProjectSchemaKey struct {
+	ID           string
+	ProjectID    string
+	Key          string
+	Type         string
+	Description  sql.NullString
+	Environments sql.NullString
+	CreatedAt    sql.NullTime
+	UpdatedAt    sql.NullTime
+}
+
 type ProjectUser struct {
-	ID        string
-	ProjectID string
-	UserID    string
-	Role      string
-	CreatedAt sql.NullTime
-	UpdatedAt interface{}
+	ID            string
+	ProjectID     string
+	UserID        string
+	Role          string
+	CreatedAt     sql.NullTime
+	UpdatedAt     interface{}
+	ExpiresAt     sql.NullTime
+	ElevatedUntil sql.NullTime
+}
+
+type ProjectVariable struct {
+	ID          string
+	ProjectID   string
+	Key         string
+	Value       string
+	Description sql.NullString
+	CreatedAt   sql.NullTime
+	UpdatedAt   sql.NullTime
 }
 
 type User struct {
-	ID        string
-	Name      string
-	Email     string
-	Password  string
-	CreatedAt sql.NullTime
-	UpdatedAt interface{}
-	DeletedAt sql.NullTime
+	ID         string
+	Name       string
+	Email      string
+	Password   string
+	CreatedAt  sql.NullTime
+	UpdatedAt  interface{}
+	DeletedAt  sql.NullTime
+	IsAdmin    bool
+	DisabledAt sql.NullTime
 }
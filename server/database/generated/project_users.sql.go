@@ -11,9 +11,9 @@ import (
 )
 
 const addUserToProject = `-- name: AddUserToProject :one
-INSERT INTO project_users (id, project_id, user_id, role, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?)
-RETURNING id, project_id, user_id, role, created_at, updated_at
+INSERT INTO project_users (id, project_id, user_id, role, expires_at, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING id, project_id, user_id, role, created_at, updated_at, expires_at, elevated_until
 `
 
 type AddUserToProjectParams struct {
@@ -21,6 +21,7 @@ type AddUserToProjectParams struct {
 	ProjectID string
 	UserID    string
 	Role      string
+	ExpiresAt sql.NullTime
 	CreatedAt sql.NullTime
 	UpdatedAt interface{}
 }
@@ -31,6 +32,7 @@ func (q *Queries) AddUserToProject(ctx context.Context, arg AddUserToProjectPara
 		arg.ProjectID,
 		arg.UserID,
 		arg.Role,
+		arg.ExpiresAt,
 		arg.CreatedAt,
 		arg.UpdatedAt,
 	)
@@ -42,86 +44,57 @@ func (q *Queries) AddUserToProject(ctx context.Context, arg AddUserToProjectPara
 		&i.Role,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ExpiresAt,
+		&i.ElevatedUntil,
 	)
 	return i, err
 }
 
-const canUserModifyProject = `-- name: CanUserModifyProject :one
-SELECT COUNT(*) as count
-FROM projects p
-LEFT JOIN project_users pu ON p.id = pu.project_id
-WHERE p.id = ? AND p.deleted_at IS NULL
-AND (
-    p.owner_id = ? OR 
-    (pu.user_id = ? AND pu.role = 'editor')
-)
-`
-
-type CanUserModifyProjectParams struct {
-	ID      string
-	OwnerID string
-	UserID  string
-}
-
-func (q *Queries) CanUserModifyProject(ctx context.Context, arg CanUserModifyProjectParams) (int64, error) {
-	row := q.db.QueryRowContext(ctx, canUserModifyProject, arg.ID, arg.OwnerID, arg.UserID)
-	var count int64
-	err := row.Scan(&count)
-	return count, err
-}
-
-const getAccessibleProject = `-- name: GetAccessibleProject :one
-SELECT p.id, p.name, p.description, p.git_repo, p.owner_id, p.created_at, p.updated_at, p.deleted_at
-FROM projects p
-WHERE p.id = ? AND p.deleted_at IS NULL
-AND (p.owner_id = ? OR EXISTS (
-    SELECT 1 FROM project_users pu 
-    WHERE pu.project_id = p.id AND pu.user_id = ?
-))
+const elevateProjectUser = `-- name: ElevateProjectUser :exec
+UPDATE project_users
+SET elevated_until = ?, updated_at = ?
+WHERE project_id = ? AND user_id = ?
 `
 
-type GetAccessibleProjectParams struct {
-	ID      string
-	OwnerID string
-	UserID  string
+type ElevateProjectUserParams struct {
+	ElevatedUntil sql.NullTime
+	UpdatedAt     interface{}
+	ProjectID     string
+	UserID        string
 }
 
-func (q *Queries) GetAccessibleProject(ctx context.Context, arg GetAccessibleProjectParams) (Project, error) {
-	row := q.db.QueryRowContext(ctx, getAccessibleProject, arg.ID, arg.OwnerID, arg.UserID)
-	var i Project
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.Description,
-		&i.GitRepo,
-		&i.OwnerID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.DeletedAt,
+func (q *Queries) ElevateProjectUser(ctx context.Context, arg ElevateProjectUserParams) error {
+	_, err := q.db.ExecContext(ctx, elevateProjectUser,
+		arg.ElevatedUntil,
+		arg.UpdatedAt,
+		arg.ProjectID,
+		arg.UserID,
 	)
-	return i, err
+	return err
 }
 
 const getProjectMemberRole = `-- name: GetProjectMemberRole :one
 SELECT pu.role
 FROM project_users pu
 WHERE pu.project_id = ? AND pu.user_id = ?
+AND (pu.expires_at IS NULL OR pu.expires_at > ?)
 `
 
 type GetProjectMemberRoleParams struct {
 	ProjectID string
 	UserID    string
+	Now       sql.NullTime
 }
 
 func (q *Queries) GetProjectMemberRole(ctx context.Context, arg GetProjectMemberRoleParams) (string, error) {
-	row := q.db.QueryRowContext(ctx, getProjectMemberRole, arg.ProjectID, arg.UserID)
+	row := q.db.QueryRowContext(ctx, getProjectMemberRole, arg.ProjectID, arg.UserID, arg.Now)
 	var role string
 	err := row.Scan(&role)
 	return role, err
 }
 
 const getProjectMembership = `-- name: GetProjectMembership :one
-SELECT pu.id, pu.project_id, pu.user_id, pu.role, pu.created_at, pu.updated_at
+SELECT pu.id, pu.project_id, pu.user_id, pu.role, pu.created_at, pu.updated_at, pu.expires_at, pu.elevated_until
 FROM project_users pu
 WHERE pu.project_id = ? AND pu.user_id = ?
 `
@@ -141,12 +114,14 @@ func (q *Queries) GetProjectMembership(ctx context.Context, arg GetProjectMember
 		&i.Role,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ExpiresAt,
+		&i.ElevatedUntil,
 	)
 	return i, err
 }
 
 const getProjectUsers = `-- name: GetProjectUsers :many
-SELECT pu.id, pu.project_id, pu.user_id, pu.role, pu.created_at, pu.updated_at
+SELECT pu.id, pu.project_id, pu.user_id, pu.role, pu.created_at, pu.updated_at, pu.expires_at, pu.elevated_until
 FROM project_users pu
 INNER JOIN users u ON pu.user_id = u.id
 WHERE pu.project_id = ?
@@ -169,6 +144,8 @@ func (q *Queries) GetProjectUsers(ctx context.Context, projectID string) ([]Proj
 			&i.Role,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ExpiresAt,
+			&i.ElevatedUntil,
 		); err != nil {
 			return nil, err
 		}
@@ -187,17 +164,19 @@ const getUserProjects = `-- name: GetUserProjects :many
 SELECT DISTINCT p.id, p.name, p.description, p.git_repo, p.owner_id, p.created_at, p.updated_at, p.deleted_at
 FROM projects p
 LEFT JOIN project_users pu ON p.id = pu.project_id
+    AND (pu.expires_at IS NULL OR pu.expires_at > ?)
 WHERE (p.owner_id = ? OR pu.user_id = ?) AND p.deleted_at IS NULL
 ORDER BY p.created_at DESC
 `
 
 type GetUserProjectsParams struct {
+	Now     sql.NullTime
 	OwnerID string
 	UserID  string
 }
 
 func (q *Queries) GetUserProjects(ctx context.Context, arg GetUserProjectsParams) ([]Project, error) {
-	rows, err := q.db.QueryContext(ctx, getUserProjects, arg.OwnerID, arg.UserID)
+	rows, err := q.db.QueryContext(ctx, getUserProjects, arg.Now, arg.OwnerID, arg.UserID)
 	if err != nil {
 		return nil, err
 	}
@@ -228,6 +207,31 @@ func (q *Queries) GetUserProjects(ctx context.Context, arg GetUserProjectsParams
 	return items, nil
 }
 
+const grantProjectUserRole = `-- name: GrantProjectUserRole :exec
+UPDATE project_users
+SET role = ?, expires_at = ?, updated_at = ?
+WHERE project_id = ? AND user_id = ?
+`
+
+type GrantProjectUserRoleParams struct {
+	Role      string
+	ExpiresAt sql.NullTime
+	UpdatedAt interface{}
+	ProjectID string
+	UserID    string
+}
+
+func (q *Queries) GrantProjectUserRole(ctx context.Context, arg GrantProjectUserRoleParams) error {
+	_, err := q.db.ExecContext(ctx, grantProjectUserRole,
+		arg.Role,
+		arg.ExpiresAt,
+		arg.UpdatedAt,
+		arg.ProjectID,
+		arg.UserID,
+	)
+	return err
+}
+
 const isProjectOwner = `-- name: IsProjectOwner :one
 SELECT COUNT(*) as count
 FROM projects
@@ -263,12 +267,13 @@ func (q *Queries) RemoveUserFromProject(ctx context.Context, arg RemoveUserFromP
 
 const updateUserRole = `-- name: UpdateUserRole :exec
 UPDATE project_users
-SET role = ?, updated_at = ?
+SET role = ?, expires_at = ?, elevated_until = NULL, updated_at = ?
 WHERE project_id = ? AND user_id = ?
 `
 
 type UpdateUserRoleParams struct {
 	Role      string
+	ExpiresAt sql.NullTime
 	UpdatedAt interface{}
 	ProjectID string
 	UserID    string
@@ -277,6 +282,7 @@ type UpdateUserRoleParams struct {
 func (q *Queries) UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) error {
 	_, err := q.db.ExecContext(ctx, updateUserRole,
 		arg.Role,
+		arg.ExpiresAt,
 		arg.UpdatedAt,
 		arg.ProjectID,
 		arg.UserID,
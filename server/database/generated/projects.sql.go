@@ -116,6 +116,101 @@ func (q *Queries) GetProjectByGitRepo(ctx context.Context, arg GetProjectByGitRe
 	return i, err
 }
 
+const listAllProjects = `-- name: ListAllProjects :many
+SELECT p.id, p.name, p.description, p.git_repo, p.owner_id, p.created_at, p.updated_at, p.deleted_at,
+    u.email AS owner_email, u.disabled_at AS owner_disabled_at
+FROM projects p
+INNER JOIN users u ON p.owner_id = u.id
+WHERE p.deleted_at IS NULL
+ORDER BY p.created_at DESC
+`
+
+type ListAllProjectsRow struct {
+	ID              string
+	Name            string
+	Description     sql.NullString
+	GitRepo         sql.NullString
+	OwnerID         string
+	CreatedAt       sql.NullTime
+	UpdatedAt       interface{}
+	DeletedAt       sql.NullTime
+	OwnerEmail      string
+	OwnerDisabledAt sql.NullTime
+}
+
+func (q *Queries) ListAllProjects(ctx context.Context) ([]ListAllProjectsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listAllProjects)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAllProjectsRow
+	for rows.Next() {
+		var i ListAllProjectsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.GitRepo,
+			&i.OwnerID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.OwnerEmail,
+			&i.OwnerDisabledAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProjectsByGitRepo = `-- name: ListProjectsByGitRepo :many
+SELECT id, name, description, git_repo, owner_id, created_at, updated_at, deleted_at
+FROM projects
+WHERE git_repo = ? AND deleted_at IS NULL
+ORDER BY created_at ASC
+`
+
+func (q *Queries) ListProjectsByGitRepo(ctx context.Context, gitRepo sql.NullString) ([]Project, error) {
+	rows, err := q.db.QueryContext(ctx, listProjectsByGitRepo, gitRepo)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Project
+	for rows.Next() {
+		var i Project
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.GitRepo,
+			&i.OwnerID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listProjectsByOwner = `-- name: ListProjectsByOwner :many
 SELECT id, name, description, git_repo, owner_id, created_at, updated_at, deleted_at
 FROM projects
@@ -155,6 +250,35 @@ func (q *Queries) ListProjectsByOwner(ctx context.Context, ownerID string) ([]Pr
 	return items, nil
 }
 
+const transferProjectOwnership = `-- name: TransferProjectOwnership :one
+UPDATE projects
+SET owner_id = ?, updated_at = ?
+WHERE id = ? AND deleted_at IS NULL
+RETURNING id, name, description, git_repo, owner_id, created_at, updated_at, deleted_at
+`
+
+type TransferProjectOwnershipParams struct {
+	OwnerID   string
+	UpdatedAt interface{}
+	ID        string
+}
+
+func (q *Queries) TransferProjectOwnership(ctx context.Context, arg TransferProjectOwnershipParams) (Project, error) {
+	row := q.db.QueryRowContext(ctx, transferProjectOwnership, arg.OwnerID, arg.UpdatedAt, arg.ID)
+	var i Project
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.GitRepo,
+		&i.OwnerID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
 const updateProject = `-- name: UpdateProject :one
 UPDATE projects
 SET name = ?, description = ?, git_repo = ?, updated_at = ?
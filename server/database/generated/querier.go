@@ -6,47 +6,74 @@ package database
 
 import (
 	"context"
+	"database/sql"
 )
 
 type Querier interface {
 	AddUserToProject(ctx context.Context, arg AddUserToProjectParams) (ProjectUser, error)
-	CanUserModifyEnvironment(ctx context.Context, arg CanUserModifyEnvironmentParams) (int64, error)
-	CanUserModifyEnvironmentVariable(ctx context.Context, arg CanUserModifyEnvironmentVariableParams) (int64, error)
-	CanUserModifyProject(ctx context.Context, arg CanUserModifyProjectParams) (int64, error)
+	CreateAuditLog(ctx context.Context, arg CreateAuditLogParams) error
 	CreateEnvironment(ctx context.Context, arg CreateEnvironmentParams) (Environment, error)
 	CreateEnvironmentVariable(ctx context.Context, arg CreateEnvironmentVariableParams) (EnvironmentVariable, error)
 	CreateProject(ctx context.Context, arg CreateProjectParams) (Project, error)
+	CreateProjectAccessRequest(ctx context.Context, arg CreateProjectAccessRequestParams) (ProjectAccessRequest, error)
+	CreateProjectSchemaKey(ctx context.Context, arg CreateProjectSchemaKeyParams) (ProjectSchemaKey, error)
+	CreateProjectVariable(ctx context.Context, arg CreateProjectVariableParams) (ProjectVariable, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
 	DeleteEnvironment(ctx context.Context, arg DeleteEnvironmentParams) error
 	DeleteEnvironmentVariable(ctx context.Context, id string) error
+	DeleteEnvironmentVariableByKey(ctx context.Context, arg DeleteEnvironmentVariableByKeyParams) (int64, error)
 	DeleteProject(ctx context.Context, arg DeleteProjectParams) error
+	DeleteProjectSchemaKeys(ctx context.Context, projectID string) error
+	DeleteProjectVariable(ctx context.Context, id string) error
 	DeleteUser(ctx context.Context, arg DeleteUserParams) error
-	GetAccessibleEnvironment(ctx context.Context, arg GetAccessibleEnvironmentParams) (Environment, error)
-	GetAccessibleEnvironmentVariable(ctx context.Context, arg GetAccessibleEnvironmentVariableParams) (EnvironmentVariable, error)
-	GetAccessibleProject(ctx context.Context, arg GetAccessibleProjectParams) (Project, error)
+	ElevateProjectUser(ctx context.Context, arg ElevateProjectUserParams) error
 	GetEnvironment(ctx context.Context, id string) (Environment, error)
 	GetEnvironmentVariable(ctx context.Context, id string) (EnvironmentVariable, error)
+	GetEnvironmentVariableByKey(ctx context.Context, arg GetEnvironmentVariableByKeyParams) (EnvironmentVariable, error)
+	GetInstanceStats(ctx context.Context) (GetInstanceStatsRow, error)
+	GetPendingProjectAccessRequest(ctx context.Context, arg GetPendingProjectAccessRequestParams) (ProjectAccessRequest, error)
 	GetProject(ctx context.Context, id string) (Project, error)
+	GetProjectAccessRequest(ctx context.Context, arg GetProjectAccessRequestParams) (ProjectAccessRequest, error)
 	GetProjectByGitRepo(ctx context.Context, arg GetProjectByGitRepoParams) (Project, error)
 	GetProjectMemberRole(ctx context.Context, arg GetProjectMemberRoleParams) (string, error)
 	GetProjectMembership(ctx context.Context, arg GetProjectMembershipParams) (ProjectUser, error)
 	GetProjectUsers(ctx context.Context, projectID string) ([]ProjectUser, error)
+	GetProjectVariable(ctx context.Context, id string) (ProjectVariable, error)
+	GetProjectVariableByKey(ctx context.Context, arg GetProjectVariableByKeyParams) (ProjectVariable, error)
 	GetUser(ctx context.Context, id string) (User, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserProjects(ctx context.Context, arg GetUserProjectsParams) ([]Project, error)
+	GrantProjectUserRole(ctx context.Context, arg GrantProjectUserRoleParams) error
 	HardDeleteUser(ctx context.Context, id string) error
 	IsProjectOwner(ctx context.Context, arg IsProjectOwnerParams) (int64, error)
+	ListAllProjects(ctx context.Context) ([]ListAllProjectsRow, error)
 	ListEnvironmentVariablesByEnvironment(ctx context.Context, environmentID string) ([]EnvironmentVariable, error)
 	ListEnvironmentsByProject(ctx context.Context, projectID string) ([]Environment, error)
+	ListProjectAccessRequests(ctx context.Context, arg ListProjectAccessRequestsParams) ([]ListProjectAccessRequestsRow, error)
+	ListProjectAuditLogs(ctx context.Context, arg ListProjectAuditLogsParams) ([]ListProjectAuditLogsRow, error)
+	ListProjectSchemaKeys(ctx context.Context, projectID string) ([]ProjectSchemaKey, error)
+	ListProjectVariablesByProject(ctx context.Context, projectID string) ([]ProjectVariable, error)
+	ListProjectsByGitRepo(ctx context.Context, gitRepo sql.NullString) ([]Project, error)
 	ListProjectsByOwner(ctx context.Context, ownerID string) ([]Project, error)
+	ListUserEnvironmentVariables(ctx context.Context, arg ListUserEnvironmentVariablesParams) ([]ListUserEnvironmentVariablesRow, error)
+	ListUserProjectVariables(ctx context.Context, arg ListUserProjectVariablesParams) ([]ListUserProjectVariablesRow, error)
+	ListUserRotationVariables(ctx context.Context, arg ListUserRotationVariablesParams) ([]ListUserRotationVariablesRow, error)
 	ListUsers(ctx context.Context) ([]User, error)
 	RemoveUserFromProject(ctx context.Context, arg RemoveUserFromProjectParams) error
+	ReviewProjectAccessRequest(ctx context.Context, arg ReviewProjectAccessRequestParams) (ProjectAccessRequest, error)
 	SearchUsersByEmail(ctx context.Context, email string) ([]User, error)
+	SetUserAdminByEmail(ctx context.Context, arg SetUserAdminByEmailParams) error
+	SetUserDisabled(ctx context.Context, arg SetUserDisabledParams) (User, error)
+	TransferProjectOwnership(ctx context.Context, arg TransferProjectOwnershipParams) (Project, error)
 	UpdateEnvironment(ctx context.Context, arg UpdateEnvironmentParams) (Environment, error)
 	UpdateEnvironmentVariable(ctx context.Context, arg UpdateEnvironmentVariableParams) (EnvironmentVariable, error)
+	UpdateEnvironmentVariableLabels(ctx context.Context, arg UpdateEnvironmentVariableLabelsParams) (EnvironmentVariable, error)
+	UpdateEnvironmentVariableRotation(ctx context.Context, arg UpdateEnvironmentVariableRotationParams) (EnvironmentVariable, error)
 	UpdateProject(ctx context.Context, arg UpdateProjectParams) (Project, error)
+	UpdateProjectVariable(ctx context.Context, arg UpdateProjectVariableParams) (ProjectVariable, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
 	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) error
+	UpsertEnvironmentVariable(ctx context.Context, arg UpsertEnvironmentVariableParams) (EnvironmentVariable, error)
 }
 
 var _ Querier = (*Queries)(nil)
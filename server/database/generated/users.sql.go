@@ -12,11 +12,11 @@ import (
 
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (
-  id, name, email, password, created_at, updated_at, deleted_at
+  id, name, email, password, created_at, updated_at, deleted_at, is_admin
 ) VALUES (
-  ?, ?, ?, ?, ?, ?, ?
+  ?, ?, ?, ?, ?, ?, ?, ?
 )
-RETURNING id, name, email, password, created_at, updated_at, deleted_at
+RETURNING id, name, email, password, created_at, updated_at, deleted_at, is_admin, disabled_at
 `
 
 type CreateUserParams struct {
@@ -27,6 +27,7 @@ type CreateUserParams struct {
 	CreatedAt sql.NullTime
 	UpdatedAt interface{}
 	DeletedAt sql.NullTime
+	IsAdmin   bool
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
@@ -38,6 +39,7 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		arg.CreatedAt,
 		arg.UpdatedAt,
 		arg.DeletedAt,
+		arg.IsAdmin,
 	)
 	var i User
 	err := row.Scan(
@@ -48,6 +50,8 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.IsAdmin,
+		&i.DisabledAt,
 	)
 	return i, err
 }
@@ -69,7 +73,7 @@ func (q *Queries) DeleteUser(ctx context.Context, arg DeleteUserParams) error {
 }
 
 const getUser = `-- name: GetUser :one
-SELECT id, name, email, password, created_at, updated_at, deleted_at FROM users
+SELECT id, name, email, password, created_at, updated_at, deleted_at, is_admin, disabled_at FROM users
 WHERE id = ? LIMIT 1
 `
 
@@ -84,12 +88,14 @@ func (q *Queries) GetUser(ctx context.Context, id string) (User, error) {
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.IsAdmin,
+		&i.DisabledAt,
 	)
 	return i, err
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, name, email, password, created_at, updated_at, deleted_at FROM users
+SELECT id, name, email, password, created_at, updated_at, deleted_at, is_admin, disabled_at FROM users
 WHERE email = ? AND deleted_at IS NULL LIMIT 1
 `
 
@@ -104,6 +110,8 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.IsAdmin,
+		&i.DisabledAt,
 	)
 	return i, err
 }
@@ -119,7 +127,7 @@ func (q *Queries) HardDeleteUser(ctx context.Context, id string) error {
 }
 
 const listUsers = `-- name: ListUsers :many
-SELECT id, name, email, password, created_at, updated_at, deleted_at FROM users
+SELECT id, name, email, password, created_at, updated_at, deleted_at, is_admin, disabled_at FROM users
 WHERE deleted_at IS NULL
 ORDER BY created_at DESC
 `
@@ -141,6 +149,8 @@ func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.IsAdmin,
+			&i.DisabledAt,
 		); err != nil {
 			return nil, err
 		}
@@ -156,7 +166,7 @@ func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
 }
 
 const searchUsersByEmail = `-- name: SearchUsersByEmail :many
-SELECT id, name, email, password, created_at, updated_at, deleted_at FROM users
+SELECT id, name, email, password, created_at, updated_at, deleted_at, is_admin, disabled_at FROM users
 WHERE email LIKE ? AND deleted_at IS NULL
 ORDER BY email ASC
 LIMIT 10
@@ -179,6 +189,8 @@ func (q *Queries) SearchUsersByEmail(ctx context.Context, email string) ([]User,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.IsAdmin,
+			&i.DisabledAt,
 		); err != nil {
 			return nil, err
 		}
@@ -193,11 +205,58 @@ func (q *Queries) SearchUsersByEmail(ctx context.Context, email string) ([]User,
 	return items, nil
 }
 
+const setUserAdminByEmail = `-- name: SetUserAdminByEmail :exec
+UPDATE users
+SET is_admin = ?, updated_at = ?
+WHERE lower(email) = lower(?) AND deleted_at IS NULL
+`
+
+type SetUserAdminByEmailParams struct {
+	IsAdmin   bool
+	UpdatedAt interface{}
+	Email     string
+}
+
+func (q *Queries) SetUserAdminByEmail(ctx context.Context, arg SetUserAdminByEmailParams) error {
+	_, err := q.db.ExecContext(ctx, setUserAdminByEmail, arg.IsAdmin, arg.UpdatedAt, arg.Email)
+	return err
+}
+
+const setUserDisabled = `-- name: SetUserDisabled :one
+UPDATE users
+SET disabled_at = ?, updated_at = ?
+WHERE id = ? AND deleted_at IS NULL
+RETURNING id, name, email, password, created_at, updated_at, deleted_at, is_admin, disabled_at
+`
+
+type SetUserDisabledParams struct {
+	DisabledAt sql.NullTime
+	UpdatedAt  interface{}
+	ID         string
+}
+
+func (q *Queries) SetUserDisabled(ctx context.Context, arg SetUserDisabledParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, setUserDisabled, arg.DisabledAt, arg.UpdatedAt, arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.Password,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.IsAdmin,
+		&i.DisabledAt,
+	)
+	return i, err
+}
+
 const updateUser = `-- name: UpdateUser :one
 UPDATE users
 SET name = ?, email = ?, password = ?, updated_at = ?
 WHERE id = ?
-RETURNING id, name, email, password, created_at, updated_at, deleted_at
+RETURNING id, name, email, password, created_at, updated_at, deleted_at, is_admin, disabled_at
 `
 
 type UpdateUserParams struct {
@@ -225,6 +284,8 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.IsAdmin,
+		&i.DisabledAt,
 	)
 	return i, err
 }
@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE project_access_requests (
+    id text PRIMARY KEY,
+    project_id text NOT NULL,
+    user_id text NOT NULL,
+    role text NOT NULL DEFAULT 'viewer',
+    message TEXT,
+    status text NOT NULL DEFAULT 'pending',
+    reviewed_by text,
+    reviewed_at TIMESTAMP DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE project_access_requests;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE project_users ADD COLUMN expires_at TIMESTAMP DEFAULT NULL;
+ALTER TABLE project_users ADD COLUMN elevated_until TIMESTAMP DEFAULT NULL;
+
+-- +goose Down
+ALTER TABLE project_users DROP COLUMN elevated_until;
+ALTER TABLE project_users DROP COLUMN expires_at;
@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE audit_logs (
+    id text PRIMARY KEY,
+    project_id text,
+    user_id text NOT NULL,
+    action text NOT NULL,
+    details TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE audit_logs;
@@ -0,0 +1,19 @@
+-- +goose Up
+-- Keep only the most recently updated row for each duplicated key before
+-- enforcing uniqueness.
+DELETE FROM environment_variables
+WHERE id NOT IN (
+    SELECT id FROM (
+        SELECT id, ROW_NUMBER() OVER (
+            PARTITION BY environment_id, key
+            ORDER BY updated_at DESC, created_at DESC, id DESC
+        ) AS row_num
+        FROM environment_variables
+    )
+    WHERE row_num = 1
+);
+
+CREATE UNIQUE INDEX idx_environment_variables_environment_key ON environment_variables (environment_id, key);
+
+-- +goose Down
+DROP INDEX idx_environment_variables_environment_key;
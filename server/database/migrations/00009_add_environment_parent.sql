@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE environments ADD COLUMN parent_id text REFERENCES environments(id) ON DELETE SET NULL;
+
+-- +goose Down
+ALTER TABLE environments DROP COLUMN parent_id;
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE project_variables (
+    id text PRIMARY KEY,
+    project_id text NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_project_variables_project_key ON project_variables (project_id, key);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_project_variables_project_key;
+DROP TABLE project_variables;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE environment_variables ADD COLUMN type TEXT NOT NULL DEFAULT 'string';
+ALTER TABLE environment_variables ADD COLUMN allowed_values TEXT;
+
+-- +goose Down
+ALTER TABLE environment_variables DROP COLUMN allowed_values;
+ALTER TABLE environment_variables DROP COLUMN type;
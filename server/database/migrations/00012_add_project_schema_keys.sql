@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE project_schema_keys (
+    id text PRIMARY KEY,
+    project_id text NOT NULL,
+    key TEXT NOT NULL,
+    type TEXT NOT NULL DEFAULT 'string',
+    description TEXT,
+    environments TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_project_schema_keys_project_key ON project_schema_keys (project_id, key);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_project_schema_keys_project_key;
+DROP TABLE project_schema_keys;
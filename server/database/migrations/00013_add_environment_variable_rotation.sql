@@ -0,0 +1,10 @@
+-- +goose Up
+ALTER TABLE environment_variables ADD COLUMN rotate_after TEXT;
+ALTER TABLE environment_variables ADD COLUMN expires_at TIMESTAMP;
+ALTER TABLE environment_variables ADD COLUMN rotated_at TIMESTAMP;
+UPDATE environment_variables SET rotated_at = updated_at;
+
+-- +goose Down
+ALTER TABLE environment_variables DROP COLUMN rotated_at;
+ALTER TABLE environment_variables DROP COLUMN expires_at;
+ALTER TABLE environment_variables DROP COLUMN rotate_after;
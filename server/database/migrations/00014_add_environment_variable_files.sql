@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE environment_variables ADD COLUMN filename TEXT;
+ALTER TABLE environment_variables ADD COLUMN file_mode TEXT;
+
+-- +goose Down
+ALTER TABLE environment_variables DROP COLUMN file_mode;
+ALTER TABLE environment_variables DROP COLUMN filename;
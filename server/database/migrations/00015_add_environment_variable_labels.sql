@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE environment_variables ADD COLUMN labels TEXT;
+
+-- +goose Down
+ALTER TABLE environment_variables DROP COLUMN labels;
@@ -0,0 +1,7 @@
+-- name: GetInstanceStats :one
+SELECT
+    (SELECT COUNT(*) FROM users WHERE deleted_at IS NULL) AS user_count,
+    (SELECT COUNT(*) FROM users WHERE deleted_at IS NULL AND disabled_at IS NOT NULL) AS disabled_user_count,
+    (SELECT COUNT(*) FROM projects WHERE deleted_at IS NULL) AS project_count,
+    (SELECT COUNT(*) FROM environments WHERE deleted_at IS NULL) AS environment_count,
+    (SELECT COUNT(*) FROM environment_variables) AS variable_count;
@@ -0,0 +1,11 @@
+-- name: CreateAuditLog :exec
+INSERT INTO audit_logs (id, project_id, user_id, action, details, created_at)
+VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: ListProjectAuditLogs :many
+SELECT al.id, al.project_id, al.user_id, al.action, al.details, al.created_at, u.email AS user_email
+FROM audit_logs al
+INNER JOIN users u ON al.user_id = u.id
+WHERE al.project_id = ?
+ORDER BY al.created_at DESC
+LIMIT ?;
@@ -1,51 +1,83 @@
 -- name: CreateEnvironmentVariable :one
-INSERT INTO environment_variables (id, environment_id, key, value, description, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, ?)
-RETURNING id, environment_id, key, value, description, created_at, updated_at;
+INSERT INTO environment_variables (id, environment_id, key, value, description, created_at, updated_at, type, allowed_values, rotate_after, expires_at, rotated_at, filename, file_mode, labels)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, environment_id, key, value, description, created_at, updated_at, type, allowed_values, rotate_after, expires_at, rotated_at, filename, file_mode, labels;
 
 -- name: GetEnvironmentVariable :one
-SELECT id, environment_id, key, value, description, created_at, updated_at
+SELECT id, environment_id, key, value, description, created_at, updated_at, type, allowed_values, rotate_after, expires_at, rotated_at, filename, file_mode, labels
 FROM environment_variables
 WHERE id = ?;
 
+-- name: GetEnvironmentVariableByKey :one
+SELECT id, environment_id, key, value, description, created_at, updated_at, type, allowed_values, rotate_after, expires_at, rotated_at, filename, file_mode, labels
+FROM environment_variables
+WHERE environment_id = ? AND key = ?;
+
 -- name: ListEnvironmentVariablesByEnvironment :many
-SELECT id, environment_id, key, value, description, created_at, updated_at
+SELECT id, environment_id, key, value, description, created_at, updated_at, type, allowed_values, rotate_after, expires_at, rotated_at, filename, file_mode, labels
 FROM environment_variables
 WHERE environment_id = ?
 ORDER BY created_at DESC;
 
 -- name: UpdateEnvironmentVariable :one
 UPDATE environment_variables
-SET key = ?, value = ?, description = ?, updated_at = ?
+SET key = ?, value = ?, description = ?, updated_at = ?, type = ?, allowed_values = ?, rotated_at = ?, expires_at = ?, filename = ?, file_mode = ?, labels = ?
 WHERE id = ?
-RETURNING id, environment_id, key, value, description, created_at, updated_at;
+RETURNING id, environment_id, key, value, description, created_at, updated_at, type, allowed_values, rotate_after, expires_at, rotated_at, filename, file_mode, labels;
+
+-- name: UpsertEnvironmentVariable :one
+INSERT INTO environment_variables (id, environment_id, key, value, description, created_at, updated_at, type, allowed_values, rotate_after, expires_at, rotated_at, filename, file_mode, labels)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (environment_id, key) DO UPDATE
+SET value = excluded.value, description = COALESCE(excluded.description, environment_variables.description), updated_at = excluded.updated_at,
+    rotated_at = CASE WHEN excluded.value = environment_variables.value THEN environment_variables.rotated_at ELSE excluded.rotated_at END,
+    expires_at = CASE WHEN excluded.value = environment_variables.value THEN environment_variables.expires_at ELSE NULL END
+RETURNING id, environment_id, key, value, description, created_at, updated_at, type, allowed_values, rotate_after, expires_at, rotated_at, filename, file_mode, labels;
 
 -- name: DeleteEnvironmentVariable :exec
 DELETE FROM environment_variables
 WHERE id = ?;
 
--- name: GetAccessibleEnvironmentVariable :one
-SELECT ev.id, ev.environment_id, ev.key, ev.value, ev.description, ev.created_at, ev.updated_at
+-- name: DeleteEnvironmentVariableByKey :execrows
+DELETE FROM environment_variables
+WHERE environment_id = ? AND key = ?;
+
+-- name: UpdateEnvironmentVariableRotation :one
+UPDATE environment_variables
+SET rotate_after = ?, expires_at = ?, updated_at = ?
+WHERE id = ?
+RETURNING id, environment_id, key, value, description, created_at, updated_at, type, allowed_values, rotate_after, expires_at, rotated_at, filename, file_mode, labels;
+
+-- name: ListUserEnvironmentVariables :many
+SELECT ev.id, ev.key, ev.value, ev.type,
+    e.id AS environment_id, e.name AS environment_name, p.id AS project_id, p.name AS project_name,
+    p.owner_id, pu.role AS member_role, pu.elevated_until
 FROM environment_variables ev
 INNER JOIN environments e ON ev.environment_id = e.id
-WHERE ev.id = ? AND e.deleted_at IS NULL
-AND (EXISTS (
-    SELECT 1 FROM projects p 
-    WHERE p.id = e.project_id AND p.deleted_at IS NULL
-    AND (p.owner_id = ? OR EXISTS (
-        SELECT 1 FROM project_users pu 
-        WHERE pu.project_id = p.id AND pu.user_id = ?
-    ))
-));
-
--- name: CanUserModifyEnvironmentVariable :one
-SELECT COUNT(*) as count
+INNER JOIN projects p ON e.project_id = p.id
+LEFT JOIN project_users pu ON pu.project_id = p.id AND pu.user_id = sqlc.arg(user_id)
+    AND (pu.expires_at IS NULL OR pu.expires_at > sqlc.arg(now))
+WHERE e.deleted_at IS NULL AND p.deleted_at IS NULL
+AND (p.owner_id = sqlc.arg(user_id) OR pu.id IS NOT NULL)
+ORDER BY p.name, e.name, ev.key;
+
+-- name: ListUserRotationVariables :many
+SELECT ev.id, ev.key, ev.type, ev.updated_at, ev.rotate_after, ev.expires_at, ev.rotated_at,
+    e.id AS environment_id, e.name AS environment_name, p.id AS project_id, p.name AS project_name
 FROM environment_variables ev
 INNER JOIN environments e ON ev.environment_id = e.id
 INNER JOIN projects p ON e.project_id = p.id
-LEFT JOIN project_users pu ON p.id = pu.project_id
-WHERE ev.id = ? AND e.deleted_at IS NULL AND p.deleted_at IS NULL
-AND (
-    p.owner_id = ? OR 
-    (pu.user_id = ? AND pu.role = 'editor')
-);
+WHERE (ev.rotate_after IS NOT NULL OR ev.expires_at IS NOT NULL)
+AND e.deleted_at IS NULL AND p.deleted_at IS NULL
+AND (p.owner_id = sqlc.arg(user_id) OR EXISTS (
+    SELECT 1 FROM project_users pu
+    WHERE pu.project_id = p.id AND pu.user_id = sqlc.arg(user_id)
+    AND (pu.expires_at IS NULL OR pu.expires_at > sqlc.arg(now))
+))
+ORDER BY p.name, e.name, ev.key;
+
+-- name: UpdateEnvironmentVariableLabels :one
+UPDATE environment_variables
+SET labels = ?, updated_at = ?
+WHERE id = ?
+RETURNING id, environment_id, key, value, description, created_at, updated_at, type, allowed_values, rotate_after, expires_at, rotated_at, filename, file_mode, labels;
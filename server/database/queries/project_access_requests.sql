@@ -0,0 +1,28 @@
+-- name: CreateProjectAccessRequest :one
+INSERT INTO project_access_requests (id, project_id, user_id, role, message, status, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, project_id, user_id, role, message, status, reviewed_by, reviewed_at, created_at, updated_at;
+
+-- name: GetProjectAccessRequest :one
+SELECT id, project_id, user_id, role, message, status, reviewed_by, reviewed_at, created_at, updated_at
+FROM project_access_requests
+WHERE id = ? AND project_id = ?;
+
+-- name: GetPendingProjectAccessRequest :one
+SELECT id, project_id, user_id, role, message, status, reviewed_by, reviewed_at, created_at, updated_at
+FROM project_access_requests
+WHERE project_id = ? AND user_id = ? AND status = 'pending';
+
+-- name: ListProjectAccessRequests :many
+SELECT par.id, par.project_id, par.user_id, par.role, par.message, par.status, par.reviewed_by, par.reviewed_at, par.created_at, par.updated_at,
+    u.name AS user_name, u.email AS user_email
+FROM project_access_requests par
+INNER JOIN users u ON par.user_id = u.id
+WHERE par.project_id = ? AND par.status = ?
+ORDER BY par.created_at ASC;
+
+-- name: ReviewProjectAccessRequest :one
+UPDATE project_access_requests
+SET status = ?, reviewed_by = ?, reviewed_at = ?, updated_at = ?
+WHERE id = ? AND status = 'pending'
+RETURNING id, project_id, user_id, role, message, status, reviewed_by, reviewed_at, created_at, updated_at;
@@ -0,0 +1,14 @@
+-- name: CreateProjectSchemaKey :one
+INSERT INTO project_schema_keys (id, project_id, key, type, description, environments, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, project_id, key, type, description, environments, created_at, updated_at;
+
+-- name: ListProjectSchemaKeys :many
+SELECT id, project_id, key, type, description, environments, created_at, updated_at
+FROM project_schema_keys
+WHERE project_id = ?
+ORDER BY key;
+
+-- name: DeleteProjectSchemaKeys :exec
+DELETE FROM project_schema_keys
+WHERE project_id = ?;
@@ -0,0 +1,41 @@
+-- name: CreateProjectVariable :one
+INSERT INTO project_variables (id, project_id, key, value, description, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING id, project_id, key, value, description, created_at, updated_at;
+
+-- name: GetProjectVariable :one
+SELECT id, project_id, key, value, description, created_at, updated_at
+FROM project_variables
+WHERE id = ?;
+
+-- name: GetProjectVariableByKey :one
+SELECT id, project_id, key, value, description, created_at, updated_at
+FROM project_variables
+WHERE project_id = ? AND key = ?;
+
+-- name: ListProjectVariablesByProject :many
+SELECT id, project_id, key, value, description, created_at, updated_at
+FROM project_variables
+WHERE project_id = ?
+ORDER BY created_at DESC;
+
+-- name: ListUserProjectVariables :many
+SELECT pv.id, pv.key, pv.value, p.id AS project_id, p.name AS project_name,
+    p.owner_id, pu.role AS member_role, pu.elevated_until
+FROM project_variables pv
+INNER JOIN projects p ON pv.project_id = p.id
+LEFT JOIN project_users pu ON pu.project_id = p.id AND pu.user_id = sqlc.arg(user_id)
+    AND (pu.expires_at IS NULL OR pu.expires_at > sqlc.arg(now))
+WHERE p.deleted_at IS NULL
+AND (p.owner_id = sqlc.arg(user_id) OR pu.id IS NOT NULL)
+ORDER BY p.name, pv.key;
+
+-- name: UpdateProjectVariable :one
+UPDATE project_variables
+SET key = ?, value = ?, description = ?, updated_at = ?
+WHERE id = ?
+RETURNING id, project_id, key, value, description, created_at, updated_at;
+
+-- name: DeleteProjectVariable :exec
+DELETE FROM project_variables
+WHERE id = ?;
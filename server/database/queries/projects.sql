@@ -28,4 +28,24 @@ WHERE id = ? AND owner_id = ? AND deleted_at IS NULL;
 -- name: GetProjectByGitRepo :one
 SELECT id, name, description, git_repo, owner_id, created_at, updated_at, deleted_at
 FROM projects
-WHERE owner_id = ? AND git_repo = ? AND deleted_at IS NULL;
\ No newline at end of file
+WHERE owner_id = ? AND git_repo = ? AND deleted_at IS NULL;
+
+-- name: ListProjectsByGitRepo :many
+SELECT id, name, description, git_repo, owner_id, created_at, updated_at, deleted_at
+FROM projects
+WHERE git_repo = ? AND deleted_at IS NULL
+ORDER BY created_at ASC;
+
+-- name: ListAllProjects :many
+SELECT p.id, p.name, p.description, p.git_repo, p.owner_id, p.created_at, p.updated_at, p.deleted_at,
+    u.email AS owner_email, u.disabled_at AS owner_disabled_at
+FROM projects p
+INNER JOIN users u ON p.owner_id = u.id
+WHERE p.deleted_at IS NULL
+ORDER BY p.created_at DESC;
+
+-- name: TransferProjectOwnership :one
+UPDATE projects
+SET owner_id = ?, updated_at = ?
+WHERE id = ? AND deleted_at IS NULL
+RETURNING id, name, description, git_repo, owner_id, created_at, updated_at, deleted_at;
@@ -13,9 +13,9 @@ ORDER BY created_at DESC;
 
 -- name: CreateUser :one
 INSERT INTO users (
-  id, name, email, password, created_at, updated_at, deleted_at
+  id, name, email, password, created_at, updated_at, deleted_at, is_admin
 ) VALUES (
-  ?, ?, ?, ?, ?, ?, ?
+  ?, ?, ?, ?, ?, ?, ?, ?
 )
 RETURNING *;
 
@@ -39,3 +39,14 @@ SELECT * FROM users
 WHERE email LIKE ? AND deleted_at IS NULL
 ORDER BY email ASC
 LIMIT 10;
+
+-- name: SetUserAdminByEmail :exec
+UPDATE users
+SET is_admin = ?, updated_at = ?
+WHERE lower(email) = lower(?) AND deleted_at IS NULL;
+
+-- name: SetUserDisabled :one
+UPDATE users
+SET disabled_at = ?, updated_at = ?
+WHERE id = ? AND deleted_at IS NULL
+RETURNING *;
@@ -5,7 +5,9 @@ CREATE TABLE users (
   password text NOT NULL,
   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
   updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-  deleted_at TIMESTAMP DEFAULT NULL
+  deleted_at TIMESTAMP DEFAULT NULL,
+  is_admin BOOLEAN NOT NULL DEFAULT FALSE,
+  disabled_at TIMESTAMP DEFAULT NULL
 );
 
 CREATE TABLE projects (
@@ -28,6 +30,8 @@ CREATE TABLE project_users (
   role text NOT NULL DEFAULT 'viewer',
   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
   updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  expires_at TIMESTAMP DEFAULT NULL,
+  elevated_until TIMESTAMP DEFAULT NULL,
   FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
   FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
   UNIQUE(project_id, user_id)
@@ -41,7 +45,9 @@ CREATE TABLE environments (
   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
   updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
   deleted_at TIMESTAMP DEFAULT NULL,
+  parent_id text,
   FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+  FOREIGN KEY (parent_id) REFERENCES environments(id) ON DELETE SET NULL,
   UNIQUE(project_id, name)
 );
 
@@ -53,5 +59,68 @@ CREATE TABLE environment_variables (
     description TEXT,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    type TEXT NOT NULL DEFAULT 'string',
+    allowed_values TEXT,
+    rotate_after TEXT,
+    expires_at TIMESTAMP,
+    rotated_at TIMESTAMP,
+    filename TEXT,
+    file_mode TEXT,
+    labels TEXT,
     FOREIGN KEY (environment_id) REFERENCES environments(id) ON DELETE CASCADE
 );
+
+CREATE UNIQUE INDEX idx_environment_variables_environment_key ON environment_variables (environment_id, key);
+
+CREATE TABLE project_variables (
+    id text PRIMARY KEY,
+    project_id text NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_project_variables_project_key ON project_variables (project_id, key);
+
+CREATE TABLE project_schema_keys (
+    id text PRIMARY KEY,
+    project_id text NOT NULL,
+    key TEXT NOT NULL,
+    type TEXT NOT NULL DEFAULT 'string',
+    description TEXT,
+    environments TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_project_schema_keys_project_key ON project_schema_keys (project_id, key);
+
+CREATE TABLE project_access_requests (
+    id text PRIMARY KEY,
+    project_id text NOT NULL,
+    user_id text NOT NULL,
+    role text NOT NULL DEFAULT 'viewer',
+    message TEXT,
+    status text NOT NULL DEFAULT 'pending',
+    reviewed_by text,
+    reviewed_at TIMESTAMP DEFAULT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE audit_logs (
+    id text PRIMARY KEY,
+    project_id text,
+    user_id text NOT NULL,
+    action text NOT NULL,
+    details TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -58,6 +58,24 @@ func (s *Service) GetQueries() database.Querier {
 	return s.queries
 }
 
+// ExecTx runs fn with queries bound to a single transaction. The transaction is
+// committed if fn returns nil and rolled back otherwise.
+func (s *Service) ExecTx(ctx context.Context, fn func(database.Querier) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(s.queries.WithTx(tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
 // Unused
 func (s *Service) Health() (*HealthStatus, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
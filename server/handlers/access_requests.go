@@ -0,0 +1,323 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	database "ytsruh.com/envoy/server/database/generated"
+	"ytsruh.com/envoy/server/utils"
+	shared "ytsruh.com/envoy/shared"
+)
+
+const (
+	AccessRequestPending  = "pending"
+	AccessRequestApproved = "approved"
+	AccessRequestDenied   = "denied"
+)
+
+type CreateAccessRequestRequest struct {
+	ProjectID string `json:"project_id" validate:"required"`
+	Role      string `json:"role" validate:"required,oneof=metadata viewer editor"`
+	Message   string `json:"message" validate:"max=500"`
+}
+
+type DiscoverProjectResponse struct {
+	ID      string  `json:"id"`
+	Name    string  `json:"name"`
+	GitRepo *string `json:"git_repo"`
+}
+
+type AccessRequestResponse struct {
+	ID         string            `json:"id"`
+	ProjectID  shared.ProjectID  `json:"project_id"`
+	UserID     shared.UserID     `json:"user_id"`
+	UserName   string            `json:"user_name,omitempty"`
+	UserEmail  string            `json:"user_email,omitempty"`
+	Role       string            `json:"role"`
+	Message    *string           `json:"message"`
+	Status     string            `json:"status"`
+	ReviewedBy *string           `json:"reviewed_by"`
+	ReviewedAt *shared.Timestamp `json:"reviewed_at"`
+	CreatedAt  shared.Timestamp  `json:"created_at"`
+}
+
+func newAccessRequestResponse(r database.ProjectAccessRequest) AccessRequestResponse {
+	resp := AccessRequestResponse{
+		ID:         r.ID,
+		ProjectID:  shared.ProjectID(r.ProjectID),
+		UserID:     shared.UserID(r.UserID),
+		Role:       r.Role,
+		Message:    shared.NullStringToStringPtr(r.Message),
+		Status:     r.Status,
+		ReviewedBy: shared.NullStringToStringPtr(r.ReviewedBy),
+		CreatedAt:  shared.FromTime(r.CreatedAt.Time),
+	}
+	if r.ReviewedAt.Valid {
+		reviewedAt := shared.FromTime(r.ReviewedAt.Time)
+		resp.ReviewedAt = &reviewedAt
+	}
+	return resp
+}
+
+// DiscoverProjects finds projects linked to a git repository so that users who
+// are not yet members can request access to them.
+func DiscoverProjects(c echo.Context, ctx *HandlerContext) error {
+	if _, err := GetUserOrUnauthorized(c); err != nil {
+		return err
+	}
+
+	gitRepo := c.QueryParam("git_repo")
+	if gitRepo == "" {
+		return SendErrorResponse(c, http.StatusBadRequest, fmt.Errorf("git_repo query parameter is required"))
+	}
+
+	dbCtx, cancel := GetDBContext()
+	defer cancel()
+
+	projects, err := ctx.Queries.ListProjectsByGitRepo(dbCtx, sql.NullString{String: gitRepo, Valid: true})
+	if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch projects"))
+	}
+
+	var resp []DiscoverProjectResponse
+	for _, project := range projects {
+		resp = append(resp, DiscoverProjectResponse{
+			ID:      project.ID,
+			Name:    project.Name,
+			GitRepo: shared.NullStringToStringPtr(project.GitRepo),
+		})
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+func CreateAccessRequest(c echo.Context, ctx *HandlerContext) error {
+	var req CreateAccessRequestRequest
+	if err := BindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	claims, err := GetUserOrUnauthorized(c)
+	if err != nil {
+		return err
+	}
+
+	dbCtx, cancel := GetDBContext()
+	defer cancel()
+
+	project, err := ctx.Queries.GetProject(dbCtx, req.ProjectID)
+	if err == sql.ErrNoRows {
+		return SendErrorResponse(c, http.StatusNotFound, fmt.Errorf("project not found"))
+	} else if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch project"))
+	}
+
+	if project.OwnerID == claims.UserID {
+		return SendErrorResponse(c, http.StatusBadRequest, fmt.Errorf("you already own this project"))
+	}
+	if _, err := ctx.AccessControl.GetRole(dbCtx, project.ID, claims.UserID); err == nil {
+		return SendErrorResponse(c, http.StatusBadRequest, fmt.Errorf("you are already a member of this project"))
+	} else if err != shared.ErrNotMember {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to check project membership"))
+	}
+
+	_, err = ctx.Queries.GetPendingProjectAccessRequest(dbCtx, database.GetPendingProjectAccessRequestParams{
+		ProjectID: project.ID,
+		UserID:    claims.UserID,
+	})
+	if err == nil {
+		return SendErrorResponse(c, http.StatusConflict, fmt.Errorf("an access request for this project is already pending"))
+	} else if err != sql.ErrNoRows {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to check existing access requests"))
+	}
+
+	now := time.Now()
+	accessRequest, err := ctx.Queries.CreateProjectAccessRequest(dbCtx, database.CreateProjectAccessRequestParams{
+		ID:        utils.GenerateUUID(),
+		ProjectID: project.ID,
+		UserID:    claims.UserID,
+		Role:      req.Role,
+		Message:   sql.NullString{String: req.Message, Valid: req.Message != ""},
+		Status:    AccessRequestPending,
+		CreatedAt: sql.NullTime{Time: now, Valid: true},
+		UpdatedAt: sql.NullTime{Time: now, Valid: true},
+	})
+	if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to create access request"))
+	}
+
+	return c.JSON(http.StatusCreated, newAccessRequestResponse(accessRequest))
+}
+
+func ListAccessRequests(c echo.Context, ctx *HandlerContext) error {
+	resources, err := GetResources(c)
+	if err != nil {
+		return err
+	}
+	projectID := resources.Project.ID
+
+	status := c.QueryParam("status")
+	if status == "" {
+		status = AccessRequestPending
+	}
+	if status != AccessRequestPending && status != AccessRequestApproved && status != AccessRequestDenied {
+		return SendErrorResponse(c, http.StatusBadRequest, fmt.Errorf("status must be one of pending, approved or denied"))
+	}
+
+	dbCtx, cancel := GetDBContext()
+	defer cancel()
+
+	accessRequests, err := ctx.Queries.ListProjectAccessRequests(dbCtx, database.ListProjectAccessRequestsParams{
+		ProjectID: projectID,
+		Status:    status,
+	})
+	if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch access requests"))
+	}
+
+	var resp []AccessRequestResponse
+	for _, r := range accessRequests {
+		item := newAccessRequestResponse(database.ProjectAccessRequest{
+			ID:         r.ID,
+			ProjectID:  r.ProjectID,
+			UserID:     r.UserID,
+			Role:       r.Role,
+			Message:    r.Message,
+			Status:     r.Status,
+			ReviewedBy: r.ReviewedBy,
+			ReviewedAt: r.ReviewedAt,
+			CreatedAt:  r.CreatedAt,
+			UpdatedAt:  r.UpdatedAt,
+		})
+		item.UserName = r.UserName
+		item.UserEmail = r.UserEmail
+		resp = append(resp, item)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+func ApproveAccessRequest(c echo.Context, ctx *HandlerContext) error {
+	return reviewAccessRequest(c, ctx, AccessRequestApproved)
+}
+
+func DenyAccessRequest(c echo.Context, ctx *HandlerContext) error {
+	return reviewAccessRequest(c, ctx, AccessRequestDenied)
+}
+
+func reviewAccessRequest(c echo.Context, ctx *HandlerContext, status string) error {
+	requestID := c.Param("request_id")
+
+	claims, err := GetUserOrUnauthorized(c)
+	if err != nil {
+		return err
+	}
+
+	resources, err := GetResources(c)
+	if err != nil {
+		return err
+	}
+	projectID := resources.Project.ID
+
+	dbCtx, cancel := GetDBContext()
+	defer cancel()
+
+	accessRequest, err := ctx.Queries.GetProjectAccessRequest(dbCtx, database.GetProjectAccessRequestParams{
+		ID:        requestID,
+		ProjectID: projectID,
+	})
+	if err == sql.ErrNoRows {
+		return SendErrorResponse(c, http.StatusNotFound, fmt.Errorf("access request not found"))
+	} else if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch access request"))
+	}
+
+	if accessRequest.Status != AccessRequestPending {
+		return SendErrorResponse(c, http.StatusConflict, fmt.Errorf("access request has already been %s", accessRequest.Status))
+	}
+
+	// The request is marked reviewed first, and only while still pending, so
+	// that of two concurrent reviews only one can grant access.
+	var reviewed database.ProjectAccessRequest
+	var alreadyReviewed bool
+	err = ctx.Tx.ExecTx(dbCtx, func(q database.Querier) error {
+		now := time.Now()
+		reviewed, err = q.ReviewProjectAccessRequest(dbCtx, database.ReviewProjectAccessRequestParams{
+			Status:     status,
+			ReviewedBy: sql.NullString{String: claims.UserID, Valid: true},
+			ReviewedAt: sql.NullTime{Time: now, Valid: true},
+			UpdatedAt:  sql.NullTime{Time: now, Valid: true},
+			ID:         accessRequest.ID,
+		})
+		if err == sql.ErrNoRows {
+			alreadyReviewed = true
+			return err
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update access request")
+		}
+		if status != AccessRequestApproved {
+			return nil
+		}
+		return grantRequestedRole(dbCtx, q, projectID, accessRequest.UserID, accessRequest.Role, now)
+	})
+	if alreadyReviewed {
+		return SendErrorResponse(c, http.StatusConflict, fmt.Errorf("access request has already been reviewed"))
+	}
+	if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, newAccessRequestResponse(reviewed))
+}
+
+// grantRequestedRole gives a user the role they requested on a project. An
+// existing membership is only ever raised to the requested role, never
+// lowered, and keeps its expiry and any active elevation; expired memberships
+// are renewed.
+func grantRequestedRole(dbCtx context.Context, q database.Querier, projectID, userID, role string, now time.Time) error {
+	membership, err := q.GetProjectMembership(dbCtx, database.GetProjectMembershipParams{
+		ProjectID: projectID,
+		UserID:    userID,
+	})
+	if err == sql.ErrNoRows {
+		_, err = q.AddUserToProject(dbCtx, database.AddUserToProjectParams{
+			ID:        utils.GenerateUUID(),
+			ProjectID: projectID,
+			UserID:    userID,
+			Role:      role,
+			CreatedAt: sql.NullTime{Time: now, Valid: true},
+			UpdatedAt: now,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to grant project access")
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to grant project access")
+	}
+
+	expired := membership.ExpiresAt.Valid && !membership.ExpiresAt.Time.After(now)
+	expiresAt := membership.ExpiresAt
+	if expired {
+		expiresAt = sql.NullTime{}
+	} else if utils.RoleAtLeast(membership.Role, role) {
+		return nil
+	}
+	err = q.GrantProjectUserRole(dbCtx, database.GrantProjectUserRoleParams{
+		Role:      role,
+		ExpiresAt: expiresAt,
+		UpdatedAt: now,
+		ProjectID: projectID,
+		UserID:    userID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to grant project access")
+	}
+	return nil
+}
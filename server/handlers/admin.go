@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	database "ytsruh.com/envoy/server/database/generated"
+	shared "ytsruh.com/envoy/shared"
+)
+
+const (
+	AuditUserDisabled            = "user.disabled"
+	AuditUserEnabled             = "user.enabled"
+	AuditProjectOwnershipChanged = "project.ownership_transferred"
+)
+
+type TransferOwnershipRequest struct {
+	UserID string `json:"user_id" validate:"required"`
+}
+
+func newAdminUserResponse(u database.User) shared.AdminUserResponse {
+	resp := shared.AdminUserResponse{
+		UserID:    shared.UserID(u.ID),
+		Name:      u.Name,
+		Email:     u.Email,
+		IsAdmin:   u.IsAdmin,
+		CreatedAt: shared.FromTime(u.CreatedAt.Time),
+	}
+	if u.DisabledAt.Valid {
+		disabledAt := shared.FromTime(u.DisabledAt.Time)
+		resp.DisabledAt = &disabledAt
+	}
+	return resp
+}
+
+func AdminListUsers(c echo.Context, ctx *HandlerContext) error {
+	dbCtx, cancel := GetDBContext()
+	defer cancel()
+
+	users, err := ctx.Queries.ListUsers(dbCtx)
+	if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch users"))
+	}
+
+	var resp []shared.AdminUserResponse
+	for _, u := range users {
+		resp = append(resp, newAdminUserResponse(u))
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+func AdminListProjects(c echo.Context, ctx *HandlerContext) error {
+	dbCtx, cancel := GetDBContext()
+	defer cancel()
+
+	projects, err := ctx.Queries.ListAllProjects(dbCtx)
+	if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch projects"))
+	}
+
+	var resp []shared.AdminProjectResponse
+	for _, p := range projects {
+		resp = append(resp, shared.AdminProjectResponse{
+			ID:            shared.ProjectID(p.ID),
+			Name:          p.Name,
+			GitRepo:       shared.NullStringToStringPtr(p.GitRepo),
+			OwnerID:       shared.UserID(p.OwnerID),
+			OwnerEmail:    p.OwnerEmail,
+			OwnerDisabled: p.OwnerDisabledAt.Valid,
+			CreatedAt:     shared.FromTime(p.CreatedAt.Time),
+		})
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+func AdminDisableUser(c echo.Context, ctx *HandlerContext) error {
+	return setUserDisabled(c, ctx, true)
+}
+
+func AdminEnableUser(c echo.Context, ctx *HandlerContext) error {
+	return setUserDisabled(c, ctx, false)
+}
+
+func setUserDisabled(c echo.Context, ctx *HandlerContext, disabled bool) error {
+	userID := c.Param("user_id")
+
+	claims, err := GetUserOrUnauthorized(c)
+	if err != nil {
+		return err
+	}
+
+	if disabled && userID == claims.UserID {
+		return SendErrorResponse(c, http.StatusBadRequest, fmt.Errorf("you cannot disable your own account"))
+	}
+
+	dbCtx, cancel := GetDBContext()
+	defer cancel()
+
+	now := time.Now()
+	params := database.SetUserDisabledParams{
+		UpdatedAt: now,
+		ID:        userID,
+	}
+	action := AuditUserEnabled
+	if disabled {
+		params.DisabledAt = sql.NullTime{Time: now, Valid: true}
+		action = AuditUserDisabled
+	}
+
+	user, err := ctx.Queries.SetUserDisabled(dbCtx, params)
+	if err == sql.ErrNoRows {
+		return SendErrorResponse(c, http.StatusNotFound, fmt.Errorf("user not found"))
+	} else if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to update user"))
+	}
+
+	if err := recordAuditLog(dbCtx, ctx, "", claims.UserID, action, user.Email); err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to record audit log"))
+	}
+
+	return c.JSON(http.StatusOK, newAdminUserResponse(user))
+}
+
+// AdminTransferProjectOwnership reassigns a project to another user, for
+// example when the original owner has left or been disabled. The new owner's
+// existing membership is removed as ownership supersedes it.
+func AdminTransferProjectOwnership(c echo.Context, ctx *HandlerContext) error {
+	projectID := c.Param("id")
+
+	var req TransferOwnershipRequest
+	if err := BindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	claims, err := GetUserOrUnauthorized(c)
+	if err != nil {
+		return err
+	}
+
+	dbCtx, cancel := GetDBContext()
+	defer cancel()
+
+	project, err := ctx.Queries.GetProject(dbCtx, projectID)
+	if err == sql.ErrNoRows {
+		return SendErrorResponse(c, http.StatusNotFound, fmt.Errorf("project not found"))
+	} else if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch project"))
+	}
+
+	newOwner, err := ctx.Queries.GetUser(dbCtx, req.UserID)
+	if err == sql.ErrNoRows || (err == nil && newOwner.DeletedAt.Valid) {
+		return SendErrorResponse(c, http.StatusNotFound, fmt.Errorf("user not found"))
+	} else if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch user"))
+	}
+
+	if newOwner.ID == project.OwnerID {
+		return SendErrorResponse(c, http.StatusBadRequest, fmt.Errorf("user already owns this project"))
+	}
+
+	if project.GitRepo.Valid {
+		_, err := ctx.Queries.GetProjectByGitRepo(dbCtx, database.GetProjectByGitRepoParams{
+			OwnerID: newOwner.ID,
+			GitRepo: project.GitRepo,
+		})
+		if err == nil {
+			return SendErrorResponse(c, http.StatusConflict, fmt.Errorf("the new owner already has a project with this git repository"))
+		}
+	}
+
+	// The new owner no longer needs a membership, and the transfer is audited
+	// in the same transaction so it can never happen unrecorded.
+	var updatedProject database.Project
+	err = ctx.Tx.ExecTx(dbCtx, func(q database.Querier) error {
+		updatedProject, err = q.TransferProjectOwnership(dbCtx, database.TransferProjectOwnershipParams{
+			OwnerID:   newOwner.ID,
+			UpdatedAt: time.Now(),
+			ID:        project.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to transfer project ownership")
+		}
+
+		err = q.RemoveUserFromProject(dbCtx, database.RemoveUserFromProjectParams{
+			ProjectID: project.ID,
+			UserID:    newOwner.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update project membership")
+		}
+
+		details := fmt.Sprintf("from %s to %s", project.OwnerID, newOwner.ID)
+		if err := writeAuditLog(dbCtx, q, project.ID, claims.UserID, AuditProjectOwnershipChanged, details); err != nil {
+			return fmt.Errorf("failed to record audit log")
+		}
+		return nil
+	})
+	if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, shared.AdminProjectResponse{
+		ID:         shared.ProjectID(updatedProject.ID),
+		Name:       updatedProject.Name,
+		GitRepo:    shared.NullStringToStringPtr(updatedProject.GitRepo),
+		OwnerID:    shared.UserID(updatedProject.OwnerID),
+		OwnerEmail: newOwner.Email,
+		CreatedAt:  shared.FromTime(updatedProject.CreatedAt.Time),
+	})
+}
+
+func AdminGetStats(c echo.Context, ctx *HandlerContext) error {
+	dbCtx, cancel := GetDBContext()
+	defer cancel()
+
+	stats, err := ctx.Queries.GetInstanceStats(dbCtx)
+	if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch stats"))
+	}
+
+	return c.JSON(http.StatusOK, shared.InstanceStatsResponse{
+		Users:         stats.UserCount,
+		DisabledUsers: stats.DisabledUserCount,
+		Projects:      stats.ProjectCount,
+		Environments:  stats.EnvironmentCount,
+		Variables:     stats.VariableCount,
+	})
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	database "ytsruh.com/envoy/server/database/generated"
+	"ytsruh.com/envoy/server/utils"
+	shared "ytsruh.com/envoy/shared"
+)
+
+const (
+	AuditMembershipElevated = "membership.elevated"
+)
+
+// auditLogLimit caps how many entries are returned when listing a project's audit log.
+const auditLogLimit = 100
+
+type AuditLogResponse struct {
+	ID        string           `json:"id"`
+	UserID    shared.UserID    `json:"user_id"`
+	UserEmail string           `json:"user_email"`
+	Action    string           `json:"action"`
+	Details   *string          `json:"details"`
+	CreatedAt shared.Timestamp `json:"created_at"`
+}
+
+// recordAuditLog writes an audit entry for an action performed by userID on a project.
+func recordAuditLog(dbCtx context.Context, ctx *HandlerContext, projectID, userID, action, details string) error {
+	return writeAuditLog(dbCtx, ctx.Queries, projectID, userID, action, details)
+}
+
+// writeAuditLog writes an audit entry using q, so that entries can be written
+// in the same transaction as the change they record.
+func writeAuditLog(dbCtx context.Context, q database.Querier, projectID, userID, action, details string) error {
+	return q.CreateAuditLog(dbCtx, database.CreateAuditLogParams{
+		ID:        utils.GenerateUUID(),
+		ProjectID: sql.NullString{String: projectID, Valid: projectID != ""},
+		UserID:    userID,
+		Action:    action,
+		Details:   sql.NullString{String: details, Valid: details != ""},
+		CreatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+}
+
+func ListAuditLogs(c echo.Context, ctx *HandlerContext) error {
+	resources, err := GetResources(c)
+	if err != nil {
+		return err
+	}
+	projectID := resources.Project.ID
+
+	dbCtx, cancel := GetDBContext()
+	defer cancel()
+
+	logs, err := ctx.Queries.ListProjectAuditLogs(dbCtx, database.ListProjectAuditLogsParams{
+		ProjectID: sql.NullString{String: projectID, Valid: true},
+		Limit:     auditLogLimit,
+	})
+	if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch audit logs"))
+	}
+
+	var resp []AuditLogResponse
+	for _, l := range logs {
+		resp = append(resp, AuditLogResponse{
+			ID:        l.ID,
+			UserID:    shared.UserID(l.UserID),
+			UserEmail: l.UserEmail,
+			Action:    l.Action,
+			Details:   shared.NullStringToStringPtr(l.Details),
+			CreatedAt: shared.FromTime(l.CreatedAt.Time),
+		})
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
@@ -56,6 +56,7 @@ func Register(c echo.Context, ctx *HandlerContext) error {
 		CreatedAt: sql.NullTime{Time: now, Valid: true},
 		UpdatedAt: now,
 		DeletedAt: sql.NullTime{Valid: false},
+		IsAdmin:   utils.IsAdminEmail(req.Email),
 	})
 	if err != nil {
 		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to create user"))
@@ -99,6 +100,10 @@ func Login(c echo.Context, ctx *HandlerContext) error {
 		return SendErrorResponse(c, http.StatusUnauthorized, fmt.Errorf("invalid email or password"))
 	}
 
+	if user.DisabledAt.Valid {
+		return SendErrorResponse(c, http.StatusForbidden, fmt.Errorf("account is disabled"))
+	}
+
 	token, err := utils.GenerateJWT(user.ID, user.Email, ctx.JWTSecret)
 	if err != nil {
 		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to generate token"))
@@ -12,17 +12,24 @@ import (
 	"ytsruh.com/envoy/server/utils"
 )
 
+// TxRunner runs a function against queries bound to a single database transaction.
+type TxRunner interface {
+	ExecTx(ctx context.Context, fn func(database.Querier) error) error
+}
+
 type HandlerContext struct {
 	Queries       database.Querier
 	JWTSecret     string
 	AccessControl utils.AccessControlService
+	Tx            TxRunner
 }
 
-func NewHandlerContext(queries database.Querier, jwtSecret string, accessControl utils.AccessControlService) *HandlerContext {
+func NewHandlerContext(queries database.Querier, jwtSecret string, accessControl utils.AccessControlService, tx TxRunner) *HandlerContext {
 	return &HandlerContext{
 		Queries:       queries,
 		JWTSecret:     jwtSecret,
 		AccessControl: accessControl,
+		Tx:            tx,
 	}
 }
 
@@ -52,6 +59,15 @@ func GetUserOrUnauthorized(c echo.Context) (*utils.JWTClaims, error) {
 	return claims, nil
 }
 
+// GetResources returns the resources resolved by middleware.RequireResourceAccess.
+func GetResources(c echo.Context) (*utils.ResolvedResources, error) {
+	resources, ok := middleware.GetResourcesFromContext(c)
+	if !ok {
+		return nil, SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("resources not resolved"))
+	}
+	return resources, nil
+}
+
 func GetDBContext() (context.Context, context.CancelFunc) {
 	return context.WithTimeout(context.Background(), 5*time.Second)
 }
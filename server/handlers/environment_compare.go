@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/labstack/echo/v4"
+	database "ytsruh.com/envoy/server/database/generated"
+	shared "ytsruh.com/envoy/shared"
+)
+
+type EnvironmentRef struct {
+	ID   shared.EnvironmentID `json:"id"`
+	Name string               `json:"name"`
+}
+
+type CompareVariable struct {
+	Key           string          `json:"key"`
+	Value         string          `json:"value,omitempty"`
+	Origin        string          `json:"origin"`
+	InheritedFrom *EnvironmentRef `json:"inherited_from,omitempty"`
+}
+
+type ChangedVariable struct {
+	Key                 string          `json:"key"`
+	BaseValue           string          `json:"base_value,omitempty"`
+	TargetValue         string          `json:"target_value,omitempty"`
+	BaseOrigin          string          `json:"base_origin"`
+	BaseInheritedFrom   *EnvironmentRef `json:"base_inherited_from,omitempty"`
+	TargetOrigin        string          `json:"target_origin"`
+	TargetInheritedFrom *EnvironmentRef `json:"target_inherited_from,omitempty"`
+}
+
+type CompareEnvironmentsResponse struct {
+	Base         EnvironmentRef    `json:"base"`
+	Target       EnvironmentRef    `json:"target"`
+	OnlyInBase   []CompareVariable `json:"only_in_base"`
+	OnlyInTarget []CompareVariable `json:"only_in_target"`
+	Changed      []ChangedVariable `json:"changed"`
+	Unchanged    int               `json:"unchanged"`
+	Masked       bool              `json:"masked,omitempty"`
+}
+
+// findProjectEnvironment looks up an environment in a project by ID or by name.
+func findProjectEnvironment(dbCtx context.Context, ctx *HandlerContext, projectID, ref string) (*database.Environment, error) {
+	environments, err := ctx.Queries.ListEnvironmentsByProject(dbCtx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range environments {
+		if e.ID == ref {
+			return &e, nil
+		}
+	}
+	for _, e := range environments {
+		if e.Name == ref {
+			return &e, nil
+		}
+	}
+	return nil, nil
+}
+
+// inheritedFromRef returns a reference to the environment v was inherited from,
+// or nil when v is not inherited.
+func inheritedFromRef(v resolvedVariable) *EnvironmentRef {
+	if v.InheritedFrom == nil {
+		return nil
+	}
+	return &EnvironmentRef{ID: shared.EnvironmentID(v.InheritedFrom.ID), Name: v.InheritedFrom.Name}
+}
+
+// compareVariable returns v as it appears in a compare response.
+func compareVariable(v resolvedVariable) CompareVariable {
+	return CompareVariable{Key: v.Key, Value: v.Value, Origin: v.Origin(), InheritedFrom: inheritedFromRef(v)}
+}
+
+// CompareEnvironments reports the keys that differ between two environments of
+// a project. Both sides are compared as resolved, so inherited and project-level
+// variables count, and each entry reports where its value came from. Values are
+// masked unless ?reveal=true is passed, which is audited.
+func CompareEnvironments(c echo.Context, ctx *HandlerContext) error {
+	resources, err := GetResources(c)
+	if err != nil {
+		return err
+	}
+	claims, err := GetUserOrUnauthorized(c)
+	if err != nil {
+		return err
+	}
+
+	baseRef := c.QueryParam("base")
+	targetRef := c.QueryParam("target")
+	if baseRef == "" || targetRef == "" {
+		return SendErrorResponse(c, http.StatusBadRequest, fmt.Errorf("base and target environments are required"))
+	}
+	reveal := c.QueryParam("reveal") == "true"
+
+	dbCtx, cancel := GetDBContext()
+	defer cancel()
+
+	projectID := resources.Project.ID
+	base, err := findProjectEnvironment(dbCtx, ctx, projectID, baseRef)
+	if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch environments"))
+	}
+	if base == nil {
+		return SendErrorResponse(c, http.StatusNotFound, fmt.Errorf("environment %s not found", baseRef))
+	}
+	target, err := findProjectEnvironment(dbCtx, ctx, projectID, targetRef)
+	if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch environments"))
+	}
+	if target == nil {
+		return SendErrorResponse(c, http.StatusNotFound, fmt.Errorf("environment %s not found", targetRef))
+	}
+
+	baseVariables, err := resolveEnvironmentVariables(dbCtx, ctx.Queries, *base)
+	if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch environment variables"))
+	}
+	targetVariables, err := resolveEnvironmentVariables(dbCtx, ctx.Queries, *target)
+	if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch environment variables"))
+	}
+
+	if reveal {
+		details := fmt.Sprintf("compare %s..%s", base.Name, target.Name)
+		if err := recordAuditLog(dbCtx, ctx, projectID, claims.UserID, AuditVariablesRevealed, details); err != nil {
+			return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to record audit log"))
+		}
+	}
+
+	resp := CompareEnvironmentsResponse{
+		Base:         EnvironmentRef{ID: shared.EnvironmentID(base.ID), Name: base.Name},
+		Target:       EnvironmentRef{ID: shared.EnvironmentID(target.ID), Name: target.Name},
+		OnlyInBase:   []CompareVariable{},
+		OnlyInTarget: []CompareVariable{},
+		Changed:      []ChangedVariable{},
+		Masked:       !reveal,
+	}
+
+	targetByKey := make(map[string]resolvedVariable, len(targetVariables))
+	for _, v := range targetVariables {
+		targetByKey[v.Key] = v
+	}
+	baseKeys := make(map[string]bool, len(baseVariables))
+	for _, v := range baseVariables {
+		baseKeys[v.Key] = true
+		t, ok := targetByKey[v.Key]
+		switch {
+		case !ok:
+			resp.OnlyInBase = append(resp.OnlyInBase, compareVariable(v))
+		case t.Value != v.Value:
+			resp.Changed = append(resp.Changed, ChangedVariable{
+				Key:                 v.Key,
+				BaseValue:           v.Value,
+				TargetValue:         t.Value,
+				BaseOrigin:          v.Origin(),
+				BaseInheritedFrom:   inheritedFromRef(v),
+				TargetOrigin:        t.Origin(),
+				TargetInheritedFrom: inheritedFromRef(t),
+			})
+		default:
+			resp.Unchanged++
+		}
+	}
+	for _, v := range targetVariables {
+		if !baseKeys[v.Key] {
+			resp.OnlyInTarget = append(resp.OnlyInTarget, compareVariable(v))
+		}
+	}
+
+	if !reveal {
+		for i := range resp.OnlyInBase {
+			resp.OnlyInBase[i].Value = ""
+		}
+		for i := range resp.OnlyInTarget {
+			resp.OnlyInTarget[i].Value = ""
+		}
+		for i := range resp.Changed {
+			resp.Changed[i].BaseValue = ""
+			resp.Changed[i].TargetValue = ""
+		}
+	}
+
+	sort.Slice(resp.OnlyInBase, func(i, j int) bool { return resp.OnlyInBase[i].Key < resp.OnlyInBase[j].Key })
+	sort.Slice(resp.OnlyInTarget, func(i, j int) bool { return resp.OnlyInTarget[i].Key < resp.OnlyInTarget[j].Key })
+	sort.Slice(resp.Changed, func(i, j int) bool { return resp.Changed[i].Key < resp.Changed[j].Key })
+
+	return c.JSON(http.StatusOK, resp)
+}
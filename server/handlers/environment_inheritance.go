@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	database "ytsruh.com/envoy/server/database/generated"
+	"ytsruh.com/envoy/server/utils"
+)
+
+// maxInheritanceDepth limits how many parents are followed when resolving an
+// environment, so a corrupted chain can never loop forever.
+const maxInheritanceDepth = 10
+
+// Origins of the variables visible in an environment.
+const (
+	VariableOriginEnvironment = "environment"
+	VariableOriginInherited   = "inherited"
+	VariableOriginProject     = "project"
+)
+
+// resolvedVariable is a variable visible in an environment together with the
+// ancestor it was inherited from, or nil when the environment defines it itself.
+// FromProject marks project-level variables, which have no environment.
+type resolvedVariable struct {
+	database.EnvironmentVariable
+	InheritedFrom *database.Environment
+	FromProject   bool
+}
+
+// Origin reports where the variable is defined.
+func (v resolvedVariable) Origin() string {
+	switch {
+	case v.FromProject:
+		return VariableOriginProject
+	case v.InheritedFrom != nil:
+		return VariableOriginInherited
+	default:
+		return VariableOriginEnvironment
+	}
+}
+
+// environmentChain returns env followed by its ancestors, nearest first.
+// Deleted parents end the chain.
+func environmentChain(dbCtx context.Context, q database.Querier, env database.Environment) ([]database.Environment, error) {
+	chain := []database.Environment{env}
+	seen := map[string]bool{env.ID: true}
+	current := env
+	for current.ParentID.Valid && len(chain) <= maxInheritanceDepth {
+		parent, err := q.GetEnvironment(dbCtx, current.ParentID.String)
+		if err == sql.ErrNoRows {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if seen[parent.ID] {
+			break
+		}
+		seen[parent.ID] = true
+		chain = append(chain, parent)
+		current = parent
+	}
+	return chain, nil
+}
+
+// resolveEnvironmentVariables returns the merged variables of env. Variables the
+// environment defines come first, followed by inherited keys from the nearest
+// ancestor that defines them and finally project-level variables that no
+// environment in the chain overrides.
+func resolveEnvironmentVariables(dbCtx context.Context, q database.Querier, env database.Environment) ([]resolvedVariable, error) {
+	chain, err := environmentChain(dbCtx, q, env)
+	if err != nil {
+		return nil, err
+	}
+
+	var resolved []resolvedVariable
+	seen := make(map[string]bool)
+	for i, e := range chain {
+		variables, err := q.ListEnvironmentVariablesByEnvironment(dbCtx, e.ID)
+		if err != nil {
+			return nil, err
+		}
+		var origin *database.Environment
+		if i > 0 {
+			origin = &chain[i]
+		}
+		for _, v := range variables {
+			if seen[v.Key] {
+				continue
+			}
+			seen[v.Key] = true
+			resolved = append(resolved, resolvedVariable{EnvironmentVariable: v, InheritedFrom: origin})
+		}
+	}
+
+	projectVariables, err := q.ListProjectVariablesByProject(dbCtx, env.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range projectVariables {
+		if seen[v.Key] {
+			continue
+		}
+		seen[v.Key] = true
+		resolved = append(resolved, resolvedVariable{
+			EnvironmentVariable: database.EnvironmentVariable{
+				ID:          v.ID,
+				Key:         v.Key,
+				Value:       v.Value,
+				Description: v.Description,
+				CreatedAt:   v.CreatedAt,
+				UpdatedAt:   v.UpdatedAt,
+				Type:        utils.VariableTypeString,
+			},
+			FromProject: true,
+		})
+	}
+	return resolved, nil
+}
+
+// validateEnvironmentParent checks that parentRef names another environment in
+// the project and that using it as the parent of environmentID would not create
+// a cycle. environmentID is empty for environments that do not exist yet. On
+// failure it returns the HTTP status to respond with.
+func validateEnvironmentParent(dbCtx context.Context, ctx *HandlerContext, projectID, environmentID, parentRef string) (*database.Environment, int, error) {
+	parent, err := findProjectEnvironment(dbCtx, ctx, projectID, parentRef)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to fetch environments")
+	}
+	if parent == nil {
+		return nil, http.StatusNotFound, fmt.Errorf("parent environment %s not found", parentRef)
+	}
+	if parent.ID == environmentID {
+		return nil, http.StatusBadRequest, fmt.Errorf("an environment cannot inherit from itself")
+	}
+
+	chain, err := environmentChain(dbCtx, ctx.Queries, *parent)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to fetch environments")
+	}
+	for _, e := range chain {
+		if e.ID == environmentID {
+			return nil, http.StatusBadRequest, fmt.Errorf("%s already inherits from this environment", parent.Name)
+		}
+	}
+	if len(chain) >= maxInheritanceDepth {
+		return nil, http.StatusBadRequest, fmt.Errorf("environments can inherit at most %d levels deep", maxInheritanceDepth)
+	}
+	return parent, 0, nil
+}
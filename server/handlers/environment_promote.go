@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	database "ytsruh.com/envoy/server/database/generated"
+	"ytsruh.com/envoy/server/utils"
+	shared "ytsruh.com/envoy/shared"
+)
+
+const (
+	AuditEnvironmentPromoted = "environment.promoted"
+)
+
+const (
+	PromotePolicySkip      = "skip"
+	PromotePolicyOverwrite = "overwrite"
+	PromotePolicyFail      = "fail"
+)
+
+const (
+	PromoteActionCreate    = "create"
+	PromoteActionOverwrite = "overwrite"
+	PromoteActionSkip      = "skip"
+	PromoteActionUnchanged = "unchanged"
+	PromoteActionConflict  = "conflict"
+)
+
+type PromoteEnvironmentRequest struct {
+	Source string   `json:"source" validate:"required"`
+	Target string   `json:"target" validate:"required"`
+	Keys   []string `json:"keys"`
+	Policy string   `json:"policy" validate:"omitempty,oneof=skip overwrite fail"`
+	DryRun bool     `json:"dry_run"`
+}
+
+type PromoteResult struct {
+	Key    string `json:"key"`
+	Action string `json:"action"`
+}
+
+type PromoteEnvironmentResponse struct {
+	Source      EnvironmentRef  `json:"source"`
+	Target      EnvironmentRef  `json:"target"`
+	Policy      string          `json:"policy"`
+	DryRun      bool            `json:"dry_run"`
+	Results     []PromoteResult `json:"results"`
+	Created     int             `json:"created"`
+	Overwritten int             `json:"overwritten"`
+	Skipped     int             `json:"skipped"`
+	Unchanged   int             `json:"unchanged"`
+	Conflicts   int             `json:"conflicts"`
+}
+
+// PromoteEnvironment copies variables from a source environment to a target
+// environment. Keys that already exist in the target with a different value are
+// handled according to the policy: skip leaves them alone, overwrite replaces
+// them and fail aborts the whole promotion. Overwritten variables keep their
+// type in the target, and the promotion is rejected if a promoted value is not
+// valid for it or would turn a file variable into a plain one or back. File
+// variables take the source's filename and mode. Nothing is written on a dry run.
+func PromoteEnvironment(c echo.Context, ctx *HandlerContext) error {
+	resources, err := GetResources(c)
+	if err != nil {
+		return err
+	}
+	claims, err := GetUserOrUnauthorized(c)
+	if err != nil {
+		return err
+	}
+
+	var req PromoteEnvironmentRequest
+	if err := BindAndValidate(c, &req); err != nil {
+		return err
+	}
+	if req.Policy == "" {
+		req.Policy = PromotePolicySkip
+	}
+
+	dbCtx, cancel := GetDBContext()
+	defer cancel()
+
+	projectID := resources.Project.ID
+	source, err := findProjectEnvironment(dbCtx, ctx, projectID, req.Source)
+	if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch environments"))
+	}
+	if source == nil {
+		return SendErrorResponse(c, http.StatusNotFound, fmt.Errorf("environment %s not found", req.Source))
+	}
+	target, err := findProjectEnvironment(dbCtx, ctx, projectID, req.Target)
+	if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch environments"))
+	}
+	if target == nil {
+		return SendErrorResponse(c, http.StatusNotFound, fmt.Errorf("environment %s not found", req.Target))
+	}
+	if source.ID == target.ID {
+		return SendErrorResponse(c, http.StatusBadRequest, fmt.Errorf("source and target environments must differ"))
+	}
+
+	resp := PromoteEnvironmentResponse{
+		Source:  EnvironmentRef{ID: shared.EnvironmentID(source.ID), Name: source.Name},
+		Target:  EnvironmentRef{ID: shared.EnvironmentID(target.ID), Name: target.Name},
+		Policy:  req.Policy,
+		DryRun:  req.DryRun,
+		Results: []PromoteResult{},
+	}
+
+	var missing, conflicts []string
+	var invalid error
+	err = ctx.Tx.ExecTx(dbCtx, func(q database.Querier) error {
+		sourceVariables, err := q.ListEnvironmentVariablesByEnvironment(dbCtx, source.ID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch environment variables")
+		}
+		targetVariables, err := q.ListEnvironmentVariablesByEnvironment(dbCtx, target.ID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch environment variables")
+		}
+
+		sourceByKey := make(map[string]database.EnvironmentVariable, len(sourceVariables))
+		for _, v := range sourceVariables {
+			sourceByKey[v.Key] = v
+		}
+		targetByKey := make(map[string]database.EnvironmentVariable, len(targetVariables))
+		for _, v := range targetVariables {
+			targetByKey[v.Key] = v
+		}
+
+		keys := req.Keys
+		if len(keys) == 0 {
+			for key := range sourceByKey {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		keys = slices.Compact(keys)
+
+		for _, key := range keys {
+			if _, ok := sourceByKey[key]; !ok {
+				missing = append(missing, key)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("keys not found in %s: %s", source.Name, strings.Join(missing, ", "))
+		}
+
+		now := time.Now()
+		for _, key := range keys {
+			variable := sourceByKey[key]
+			existing, exists := targetByKey[key]
+
+			action := PromoteActionCreate
+			switch {
+			case exists && existing.Value == variable.Value:
+				action = PromoteActionUnchanged
+			case exists && req.Policy == PromotePolicySkip:
+				action = PromoteActionSkip
+			case exists && req.Policy == PromotePolicyOverwrite:
+				action = PromoteActionOverwrite
+			case exists:
+				action = PromoteActionConflict
+				conflicts = append(conflicts, key)
+			}
+
+			switch action {
+			case PromoteActionCreate:
+				resp.Created++
+			case PromoteActionOverwrite:
+				resp.Overwritten++
+			case PromoteActionSkip:
+				resp.Skipped++
+			case PromoteActionUnchanged:
+				resp.Unchanged++
+			case PromoteActionConflict:
+				resp.Conflicts++
+			}
+			resp.Results = append(resp.Results, PromoteResult{Key: key, Action: action})
+
+			if action == PromoteActionOverwrite {
+				// Overwritten variables keep the target's type, so the value
+				// must be valid for it. File contents cannot become a plain
+				// value or the other way round.
+				if (variable.Type == utils.VariableTypeFile) != (existing.Type == utils.VariableTypeFile) {
+					invalid = fmt.Errorf("%s is a %s variable in %s but a %s variable in %s", key, variableType(variable), source.Name, variableType(existing), target.Name)
+					return invalid
+				}
+				if err := validateExistingValue(existing, variable.Value); err != nil {
+					invalid = err
+					return err
+				}
+			}
+			if req.DryRun || (action != PromoteActionCreate && action != PromoteActionOverwrite) {
+				continue
+			}
+			if action == PromoteActionOverwrite {
+				description := existing.Description
+				if variable.Description.Valid {
+					description = variable.Description
+				}
+				filename, fileMode := existing.Filename, existing.FileMode
+				if variable.Type == utils.VariableTypeFile {
+					filename, fileMode = variable.Filename, variable.FileMode
+				}
+				_, err := q.UpdateEnvironmentVariable(dbCtx, database.UpdateEnvironmentVariableParams{
+					Key:           key,
+					Value:         variable.Value,
+					Description:   description,
+					UpdatedAt:     sql.NullTime{Time: now, Valid: true},
+					Type:          variableType(existing),
+					AllowedValues: existing.AllowedValues,
+					RotatedAt:     sql.NullTime{Time: now, Valid: true},
+					Filename:      filename,
+					FileMode:      fileMode,
+					Labels:        existing.Labels,
+					ID:            existing.ID,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to promote %s", key)
+				}
+				continue
+			}
+			_, err := q.CreateEnvironmentVariable(dbCtx, database.CreateEnvironmentVariableParams{
+				ID:            utils.GenerateUUID(),
+				EnvironmentID: target.ID,
+				Key:           key,
+				Value:         variable.Value,
+				Description:   variable.Description,
+				CreatedAt:     sql.NullTime{Time: now, Valid: true},
+				UpdatedAt:     sql.NullTime{Time: now, Valid: true},
+				Type:          variableType(variable),
+				AllowedValues: variable.AllowedValues,
+				RotateAfter:   variable.RotateAfter,
+				ExpiresAt:     variable.ExpiresAt,
+				RotatedAt:     variable.RotatedAt,
+				Filename:      variable.Filename,
+				FileMode:      variable.FileMode,
+				Labels:        variable.Labels,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to promote %s", key)
+			}
+		}
+
+		if req.DryRun {
+			return nil
+		}
+		if len(conflicts) > 0 {
+			return fmt.Errorf("keys with different values in %s: %s", target.Name, strings.Join(conflicts, ", "))
+		}
+
+		details := fmt.Sprintf("%s -> %s (%s): %d created, %d overwritten, %d skipped",
+			source.Name, target.Name, req.Policy, resp.Created, resp.Overwritten, resp.Skipped)
+		if err := writeAuditLog(dbCtx, q, projectID, claims.UserID, AuditEnvironmentPromoted, details); err != nil {
+			return fmt.Errorf("failed to record audit log")
+		}
+		return nil
+	})
+	if err != nil {
+		switch {
+		case len(missing) > 0:
+			return SendErrorResponse(c, http.StatusBadRequest, err)
+		case invalid != nil:
+			return SendErrorResponse(c, http.StatusBadRequest, fmt.Errorf("promotion aborted and no changes were applied: %v", invalid))
+		case len(conflicts) > 0:
+			return SendErrorResponse(c, http.StatusConflict, fmt.Errorf("promotion aborted, %v", err))
+		default:
+			return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("promotion failed and no changes were applied: %v", err))
+		}
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
@@ -12,14 +12,35 @@ import (
 	shared "ytsruh.com/envoy/shared"
 )
 
+const (
+	AuditEnvironmentCloned = "environment.cloned"
+)
+
 type CreateEnvironmentRequest struct {
 	Name        string `json:"name" validate:"required,environment_name"`
 	Description string `json:"description" validate:"max=500"`
+	Parent      string `json:"parent"`
 }
 
+// UpdateEnvironmentRequest leaves the parent unchanged when Parent is omitted
+// and removes it when Parent is an empty string.
 type UpdateEnvironmentRequest struct {
-	Name        string `json:"name" validate:"required,environment_name"`
-	Description string `json:"description" validate:"max=500"`
+	Name        string  `json:"name" validate:"required,environment_name"`
+	Description string  `json:"description" validate:"max=500"`
+	Parent      *string `json:"parent"`
+}
+
+type CloneEnvironmentRequest struct {
+	Name            string `json:"name" validate:"required,environment_name"`
+	Description     string `json:"description" validate:"max=500"`
+	TargetProjectID string `json:"target_project_id"`
+	BlankValues     bool   `json:"blank_values"`
+}
+
+type CloneEnvironmentResponse struct {
+	Environment     EnvironmentResponse `json:"environment"`
+	VariablesCopied int                 `json:"variables_copied"`
+	SkippedKeys     []string            `json:"skipped_keys,omitempty"`
 }
 
 type EnvironmentResponse struct {
@@ -27,22 +48,29 @@ type EnvironmentResponse struct {
 	ProjectID   shared.ProjectID     `json:"project_id"`
 	Name        string               `json:"name"`
 	Description *string              `json:"description"`
+	ParentID    *string              `json:"parent_id"`
 	CreatedAt   shared.Timestamp     `json:"created_at"`
 	UpdatedAt   shared.Timestamp     `json:"updated_at"`
 }
 
-func CreateEnvironment(c echo.Context, ctx *HandlerContext) error {
-	projectID := c.Param("project_id")
+func newEnvironmentResponse(e database.Environment) EnvironmentResponse {
+	return EnvironmentResponse{
+		ID:          shared.EnvironmentID(e.ID),
+		ProjectID:   shared.ProjectID(e.ProjectID),
+		Name:        e.Name,
+		Description: shared.NullStringToStringPtr(e.Description),
+		ParentID:    shared.NullStringToStringPtr(e.ParentID),
+		CreatedAt:   shared.FromTime(e.CreatedAt.Time),
+		UpdatedAt:   shared.FromTime(e.UpdatedAt.Time),
+	}
+}
 
-	claims, err := GetUserOrUnauthorized(c)
+func CreateEnvironment(c echo.Context, ctx *HandlerContext) error {
+	resources, err := GetResources(c)
 	if err != nil {
 		return err
 	}
 
-	if err := ctx.AccessControl.RequireEditor(c.Request().Context(), projectID, claims.UserID); err != nil {
-		return SendErrorResponse(c, http.StatusForbidden, err)
-	}
-
 	var req CreateEnvironmentRequest
 	if err := BindAndValidate(c, &req); err != nil {
 		return err
@@ -51,13 +79,23 @@ func CreateEnvironment(c echo.Context, ctx *HandlerContext) error {
 	dbCtx, cancel := GetDBContext()
 	defer cancel()
 
+	var parentID sql.NullString
+	if req.Parent != "" {
+		parent, status, err := validateEnvironmentParent(dbCtx, ctx, resources.Project.ID, "", req.Parent)
+		if err != nil {
+			return SendErrorResponse(c, status, err)
+		}
+		parentID = sql.NullString{String: parent.ID, Valid: true}
+	}
+
 	now := time.Now()
 	environmentID := utils.GenerateUUID()
 	environment, err := ctx.Queries.CreateEnvironment(dbCtx, database.CreateEnvironmentParams{
 		ID:          environmentID,
 		Name:        req.Name,
 		Description: sql.NullString{String: req.Description, Valid: req.Description != ""},
-		ProjectID:   projectID,
+		ParentID:    parentID,
+		ProjectID:   resources.Project.ID,
 		CreatedAt:   sql.NullTime{Time: now, Valid: true},
 		UpdatedAt:   sql.NullTime{Time: now, Valid: true},
 	})
@@ -65,91 +103,203 @@ func CreateEnvironment(c echo.Context, ctx *HandlerContext) error {
 		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to create environment"))
 	}
 
-	resp := EnvironmentResponse{
-		ID:          shared.EnvironmentID(environment.ID),
-		ProjectID:   shared.ProjectID(environment.ProjectID),
-		Name:        environment.Name,
-		Description: shared.NullStringToStringPtr(environment.Description),
-		CreatedAt:   shared.FromTime(environment.CreatedAt.Time),
-		UpdatedAt:   shared.FromTime(environment.UpdatedAt.Time),
-	}
+	resp := newEnvironmentResponse(environment)
 
 	return c.JSON(http.StatusCreated, resp)
 }
 
-func GetEnvironment(c echo.Context, ctx *HandlerContext) error {
-	id := c.Param("id")
-
+// CloneEnvironment creates a new environment with copies of every variable in
+// the source environment, optionally in another project the caller can edit and
+// optionally with the values blanked so only the keys carry over. Keys whose
+// type does not accept an empty value are skipped when blanking. Clones in
+// another project also get copies of the inherited and project variables.
+func CloneEnvironment(c echo.Context, ctx *HandlerContext) error {
+	resources, err := GetResources(c)
+	if err != nil {
+		return err
+	}
 	claims, err := GetUserOrUnauthorized(c)
 	if err != nil {
 		return err
 	}
 
+	var req CloneEnvironmentRequest
+	if err := BindAndValidate(c, &req); err != nil {
+		return err
+	}
+
 	dbCtx, cancel := GetDBContext()
 	defer cancel()
 
-	environment, err := ctx.Queries.GetAccessibleEnvironment(dbCtx, database.GetAccessibleEnvironmentParams{
-		ID:      id,
-		OwnerID: claims.UserID,
-		UserID:  claims.UserID,
+	source := resources.Environment
+	targetProject := resources.Project
+	if req.TargetProjectID != "" && req.TargetProjectID != resources.Project.ID {
+		target, err := ctx.AccessControl.ResolveResources(dbCtx, utils.ResourceScope{ProjectID: req.TargetProjectID}, claims.UserID, utils.RoleEditor)
+		switch err {
+		case nil:
+			targetProject = target.Project
+		case shared.ErrNotFound:
+			return SendErrorResponse(c, http.StatusNotFound, fmt.Errorf("target project not found"))
+		case shared.ErrAccessDenied:
+			return SendErrorResponse(c, http.StatusForbidden, fmt.Errorf("you need editor access to the target project"))
+		default:
+			return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to check permissions"))
+		}
+	} else if !utils.RoleAtLeast(resources.Role, utils.RoleEditor) {
+		return SendErrorResponse(c, http.StatusForbidden, fmt.Errorf("you need editor access to create environments in this project"))
+	}
+
+	var resp CloneEnvironmentResponse
+	var nameTaken bool
+	err = ctx.Tx.ExecTx(dbCtx, func(q database.Querier) error {
+		existing, err := q.ListEnvironmentsByProject(dbCtx, targetProject.ID)
+		if err != nil {
+			return err
+		}
+		for _, e := range existing {
+			if e.Name == req.Name {
+				nameTaken = true
+				return fmt.Errorf("environment name taken")
+			}
+		}
+
+		now := time.Now()
+		var parentID sql.NullString
+		if targetProject.ID == source.ProjectID {
+			parentID = source.ParentID
+		}
+		environment, err := q.CreateEnvironment(dbCtx, database.CreateEnvironmentParams{
+			ID:          utils.GenerateUUID(),
+			Name:        req.Name,
+			Description: sql.NullString{String: req.Description, Valid: req.Description != ""},
+			ParentID:    parentID,
+			ProjectID:   targetProject.ID,
+			CreatedAt:   sql.NullTime{Time: now, Valid: true},
+			UpdatedAt:   sql.NullTime{Time: now, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		// Within a project the clone keeps the source's parent, so inherited
+		// and project variables still apply. A clone in another project has
+		// neither, so it gets the source's flattened variables instead.
+		var variables []database.EnvironmentVariable
+		if targetProject.ID == source.ProjectID {
+			variables, err = q.ListEnvironmentVariablesByEnvironment(dbCtx, source.ID)
+			if err != nil {
+				return err
+			}
+		} else {
+			resolved, err := resolveEnvironmentVariables(dbCtx, q, *source)
+			if err != nil {
+				return err
+			}
+			for _, v := range resolved {
+				variables = append(variables, v.EnvironmentVariable)
+			}
+		}
+		var skipped []string
+		for _, v := range variables {
+			value, expiresAt, rotatedAt := v.Value, v.ExpiresAt, v.RotatedAt
+			if req.BlankValues {
+				if utils.ValidateVariableValue(v.Type, utils.SplitAllowedValues(v.AllowedValues.String), "") != nil {
+					skipped = append(skipped, v.Key)
+					continue
+				}
+				value, expiresAt, rotatedAt = "", sql.NullTime{}, sql.NullTime{}
+			}
+			_, err := q.CreateEnvironmentVariable(dbCtx, database.CreateEnvironmentVariableParams{
+				ID:            utils.GenerateUUID(),
+				EnvironmentID: environment.ID,
+				Key:           v.Key,
+				Value:         value,
+				Description:   v.Description,
+				CreatedAt:     sql.NullTime{Time: now, Valid: true},
+				UpdatedAt:     sql.NullTime{Time: now, Valid: true},
+				Type:          variableType(v),
+				AllowedValues: v.AllowedValues,
+				RotateAfter:   v.RotateAfter,
+				ExpiresAt:     expiresAt,
+				RotatedAt:     rotatedAt,
+				Filename:      v.Filename,
+				FileMode:      v.FileMode,
+				Labels:        v.Labels,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		resp = CloneEnvironmentResponse{
+			Environment:     newEnvironmentResponse(environment),
+			VariablesCopied: len(variables) - len(skipped),
+			SkippedKeys:     skipped,
+		}
+
+		// A clone into another project is recorded in both projects, so each
+		// audit log shows where its variables went or came from.
+		details := fmt.Sprintf("%s -> %s", source.Name, req.Name)
+		if targetProject.ID != resources.Project.ID {
+			details = fmt.Sprintf("%s (project %s) -> %s (project %s)", source.Name, resources.Project.Name, req.Name, targetProject.Name)
+		}
+		if req.BlankValues {
+			details += " without values"
+		}
+		if err := writeAuditLog(dbCtx, q, resources.Project.ID, claims.UserID, AuditEnvironmentCloned, details); err != nil {
+			return err
+		}
+		if targetProject.ID != resources.Project.ID {
+			return writeAuditLog(dbCtx, q, targetProject.ID, claims.UserID, AuditEnvironmentCloned, details)
+		}
+		return nil
 	})
-	if err == sql.ErrNoRows {
-		return SendErrorResponse(c, http.StatusNotFound, fmt.Errorf("environment not found or access denied"))
-	} else if err != nil {
-		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch environment"))
+	if nameTaken {
+		return SendErrorResponse(c, http.StatusConflict, fmt.Errorf("an environment named %s already exists in this project", req.Name))
+	}
+	if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to clone environment"))
 	}
 
-	resp := EnvironmentResponse{
-		ID:          shared.EnvironmentID(environment.ID),
-		ProjectID:   shared.ProjectID(environment.ProjectID),
-		Name:        environment.Name,
-		Description: shared.NullStringToStringPtr(environment.Description),
-		CreatedAt:   shared.FromTime(environment.CreatedAt.Time),
-		UpdatedAt:   shared.FromTime(environment.UpdatedAt.Time),
+	return c.JSON(http.StatusCreated, resp)
+}
+
+func GetEnvironment(c echo.Context, ctx *HandlerContext) error {
+	resources, err := GetResources(c)
+	if err != nil {
+		return err
 	}
+	environment := resources.Environment
+
+	resp := newEnvironmentResponse(*environment)
 
 	return c.JSON(http.StatusOK, resp)
 }
 
 func ListEnvironments(c echo.Context, ctx *HandlerContext) error {
-	projectID := c.Param("project_id")
-
-	claims, err := GetUserOrUnauthorized(c)
+	resources, err := GetResources(c)
 	if err != nil {
 		return err
 	}
 
-	if err := ctx.AccessControl.RequireViewer(c.Request().Context(), projectID, claims.UserID); err != nil {
-		return SendErrorResponse(c, http.StatusForbidden, err)
-	}
-
 	dbCtx, cancel := GetDBContext()
 	defer cancel()
 
-	environments, err := ctx.Queries.ListEnvironmentsByProject(dbCtx, projectID)
+	environments, err := ctx.Queries.ListEnvironmentsByProject(dbCtx, resources.Project.ID)
 	if err != nil {
 		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch environments"))
 	}
 
 	var resp []EnvironmentResponse
 	for _, env := range environments {
-		resp = append(resp, EnvironmentResponse{
-			ID:          shared.EnvironmentID(env.ID),
-			ProjectID:   shared.ProjectID(env.ProjectID),
-			Name:        env.Name,
-			Description: shared.NullStringToStringPtr(env.Description),
-			CreatedAt:   shared.FromTime(env.CreatedAt.Time),
-			UpdatedAt:   shared.FromTime(env.UpdatedAt.Time),
-		})
+		resp = append(resp, newEnvironmentResponse(env))
 	}
 
 	return c.JSON(http.StatusOK, resp)
 }
 
 func UpdateEnvironment(c echo.Context, ctx *HandlerContext) error {
-	id := c.Param("id")
-
-	_, err := GetUserOrUnauthorized(c)
+	resources, err := GetResources(c)
 	if err != nil {
 		return err
 	}
@@ -162,42 +312,47 @@ func UpdateEnvironment(c echo.Context, ctx *HandlerContext) error {
 	dbCtx, cancel := GetDBContext()
 	defer cancel()
 
+	parentID := resources.Environment.ParentID
+	if req.Parent != nil {
+		parentID = sql.NullString{}
+		if *req.Parent != "" {
+			parent, status, err := validateEnvironmentParent(dbCtx, ctx, resources.Project.ID, resources.Environment.ID, *req.Parent)
+			if err != nil {
+				return SendErrorResponse(c, status, err)
+			}
+			parentID = sql.NullString{String: parent.ID, Valid: true}
+		}
+	}
+
 	now := time.Now()
 	updatedEnvironment, err := ctx.Queries.UpdateEnvironment(dbCtx, database.UpdateEnvironmentParams{
 		Name:        req.Name,
 		Description: sql.NullString{String: req.Description, Valid: req.Description != ""},
+		ParentID:    parentID,
 		UpdatedAt:   sql.NullTime{Time: now, Valid: true},
-		ID:          id,
+		ID:          resources.Environment.ID,
 	})
 	if err != nil {
 		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to update environment"))
 	}
 
-	resp := EnvironmentResponse{
-		ID:          shared.EnvironmentID(updatedEnvironment.ID),
-		ProjectID:   shared.ProjectID(updatedEnvironment.ProjectID),
-		Name:        updatedEnvironment.Name,
-		Description: shared.NullStringToStringPtr(updatedEnvironment.Description),
-		CreatedAt:   shared.FromTime(updatedEnvironment.CreatedAt.Time),
-		UpdatedAt:   shared.FromTime(updatedEnvironment.UpdatedAt.Time),
-	}
+	resp := newEnvironmentResponse(updatedEnvironment)
 
 	return c.JSON(http.StatusOK, resp)
 }
 
 func DeleteEnvironment(c echo.Context, ctx *HandlerContext) error {
-	id := c.Param("id")
-
-	if _, err := GetUserOrUnauthorized(c); err != nil {
+	resources, err := GetResources(c)
+	if err != nil {
 		return err
 	}
 
 	dbCtx, cancel := GetDBContext()
 	defer cancel()
 
-	err := ctx.Queries.DeleteEnvironment(dbCtx, database.DeleteEnvironmentParams{
+	err = ctx.Queries.DeleteEnvironment(dbCtx, database.DeleteEnvironmentParams{
 		DeletedAt: sql.NullTime{Time: time.Now(), Valid: true},
-		ID:        id,
+		ID:        resources.Environment.ID,
 	})
 	if err != nil {
 		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to delete environment"))
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	database "ytsruh.com/envoy/server/database/generated"
+	"ytsruh.com/envoy/server/utils"
+)
+
+// maxInterpolationDepth bounds how many references are followed while
+// expanding a single value.
+const maxInterpolationDepth = 20
+
+// interpolator expands ${KEY}, ${env:other.KEY} and ${project:name.env.KEY}
+// references in variable values. References are resolved against the merged
+// (inherited) variables of the referenced environment, and cross-project
+// references require viewer access to the other project. "$${" produces a
+// literal "${".
+type interpolator struct {
+	dbCtx     context.Context
+	ctx       *HandlerContext
+	userID    string
+	variables map[string]map[string]string
+	projects  map[string]string
+}
+
+// interpolationFrame identifies a variable being expanded, for cycle detection.
+type interpolationFrame struct {
+	id    string
+	label string
+}
+
+func newInterpolator(dbCtx context.Context, ctx *HandlerContext, userID string) *interpolator {
+	return &interpolator{
+		dbCtx:     dbCtx,
+		ctx:       ctx,
+		userID:    userID,
+		variables: make(map[string]map[string]string),
+		projects:  make(map[string]string),
+	}
+}
+
+// Expand resolves every reference in value, which belongs to env.
+func (in *interpolator) Expand(env database.Environment, value string) (string, error) {
+	return in.expand(env, value, nil)
+}
+
+func (in *interpolator) expand(env database.Environment, value string, stack []interpolationFrame) (string, error) {
+	if !strings.Contains(value, "${") {
+		return value, nil
+	}
+
+	var b strings.Builder
+	for {
+		start := strings.Index(value, "${")
+		if start < 0 {
+			b.WriteString(value)
+			return b.String(), nil
+		}
+		if start > 0 && value[start-1] == '$' {
+			b.WriteString(value[:start-1])
+			b.WriteString("${")
+			value = value[start+2:]
+			continue
+		}
+		end := strings.IndexByte(value[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated reference in %q", value)
+		}
+
+		b.WriteString(value[:start])
+		resolved, err := in.reference(env, value[start+2:start+end], stack)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(resolved)
+		value = value[start+end+1:]
+	}
+}
+
+// reference resolves the body of a single ${...} reference.
+func (in *interpolator) reference(env database.Environment, ref string, stack []interpolationFrame) (string, error) {
+	target := env
+	key := ref
+
+	switch {
+	case strings.HasPrefix(ref, "env:"):
+		envName, envKey, ok := strings.Cut(strings.TrimPrefix(ref, "env:"), ".")
+		if !ok || envName == "" || envKey == "" {
+			return "", fmt.Errorf("invalid reference ${%s}, expected ${env:environment.KEY}", ref)
+		}
+		e, err := in.environment(env.ProjectID, envName)
+		if err != nil {
+			return "", err
+		}
+		target, key = *e, envKey
+	case strings.HasPrefix(ref, "project:"):
+		parts := strings.SplitN(strings.TrimPrefix(ref, "project:"), ".", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+			return "", fmt.Errorf("invalid reference ${%s}, expected ${project:name.environment.KEY}", ref)
+		}
+		projectID, err := in.project(parts[0])
+		if err != nil {
+			return "", err
+		}
+		e, err := in.environment(projectID, parts[1])
+		if err != nil {
+			return "", err
+		}
+		target, key = *e, parts[2]
+	}
+
+	if key == "" {
+		return "", fmt.Errorf("empty reference ${}")
+	}
+	return in.resolve(target, key, stack)
+}
+
+// resolve returns the fully expanded value of key in env, detecting cycles.
+func (in *interpolator) resolve(env database.Environment, key string, stack []interpolationFrame) (string, error) {
+	frame := interpolationFrame{id: env.ID + "/" + key, label: env.Name + "." + key}
+	for i, seen := range stack {
+		if seen.id == frame.id {
+			var path []string
+			for _, f := range stack[i:] {
+				path = append(path, f.label)
+			}
+			path = append(path, frame.label)
+			return "", fmt.Errorf("circular reference: %s", strings.Join(path, " -> "))
+		}
+	}
+	if len(stack) >= maxInterpolationDepth {
+		return "", fmt.Errorf("references nested deeper than %d levels", maxInterpolationDepth)
+	}
+
+	variables, err := in.environmentVariables(env)
+	if err != nil {
+		return "", err
+	}
+	value, ok := variables[key]
+	if !ok {
+		return "", fmt.Errorf("%s is not defined in %s", key, env.Name)
+	}
+	return in.expand(env, value, append(stack, frame))
+}
+
+func (in *interpolator) environmentVariables(env database.Environment) (map[string]string, error) {
+	if variables, ok := in.variables[env.ID]; ok {
+		return variables, nil
+	}
+	resolved, err := resolveEnvironmentVariables(in.dbCtx, in.ctx.Queries, env)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch variables for %s", env.Name)
+	}
+	variables := make(map[string]string, len(resolved))
+	for _, v := range resolved {
+		variables[v.Key] = v.Value
+	}
+	in.variables[env.ID] = variables
+	return variables, nil
+}
+
+func (in *interpolator) environment(projectID, name string) (*database.Environment, error) {
+	env, err := findProjectEnvironment(in.dbCtx, in.ctx, projectID, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch environments")
+	}
+	if env == nil {
+		return nil, fmt.Errorf("environment %s not found", name)
+	}
+	return env, nil
+}
+
+// project finds the project with the given name among those the user can read.
+func (in *interpolator) project(name string) (string, error) {
+	if id, ok := in.projects[name]; ok {
+		return id, nil
+	}
+
+	projects, err := in.ctx.Queries.GetUserProjects(in.dbCtx, database.GetUserProjectsParams{
+		OwnerID: in.userID,
+		UserID:  in.userID,
+		Now:     sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch projects")
+	}
+
+	var matches []string
+	for _, p := range projects {
+		if p.Name == name {
+			matches = append(matches, p.ID)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("project %s not found", name)
+	case 1:
+	default:
+		return "", fmt.Errorf("project name %s is ambiguous", name)
+	}
+
+	if _, err := in.ctx.AccessControl.ResolveResources(in.dbCtx, utils.ResourceScope{ProjectID: matches[0]}, in.userID, utils.RoleViewer); err != nil {
+		return "", fmt.Errorf("no read access to project %s", name)
+	}
+
+	in.projects[name] = matches[0]
+	return matches[0], nil
+}
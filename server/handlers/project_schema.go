@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	database "ytsruh.com/envoy/server/database/generated"
+	"ytsruh.com/envoy/server/utils"
+	shared "ytsruh.com/envoy/shared"
+)
+
+const (
+	AuditProjectSchemaUpdated = "project.schema_updated"
+)
+
+// SchemaKey describes a key the project's environments must define. When
+// Environments is empty the key is required in every environment; otherwise
+// only in the named ones. Enum keys are not supported because a schema has no
+// allowed values to check them against.
+type SchemaKey struct {
+	Key          string   `json:"key" validate:"required"`
+	Type         string   `json:"type" validate:"variable_type,ne=enum"`
+	Description  string   `json:"description" validate:"max=500"`
+	Environments []string `json:"environments" validate:"omitempty,dive,required,excludes=0x2C"`
+}
+
+type ProjectSchemaRequest struct {
+	Keys []SchemaKey `json:"keys" validate:"dive"`
+}
+
+type ProjectSchemaResponse struct {
+	Keys []SchemaKey `json:"keys"`
+}
+
+type SchemaViolation struct {
+	Key   string `json:"key"`
+	Error string `json:"error"`
+}
+
+type EnvironmentSchemaReport struct {
+	Environment EnvironmentRef    `json:"environment"`
+	Valid       bool              `json:"valid"`
+	Missing     []string          `json:"missing"`
+	Invalid     []SchemaViolation `json:"invalid"`
+}
+
+type ValidateProjectSchemaResponse struct {
+	Valid        bool                      `json:"valid"`
+	Environments []EnvironmentSchemaReport `json:"environments"`
+}
+
+func newSchemaKey(k database.ProjectSchemaKey) SchemaKey {
+	return SchemaKey{
+		Key:          k.Key,
+		Type:         k.Type,
+		Description:  k.Description.String,
+		Environments: utils.SplitAllowedValues(k.Environments.String),
+	}
+}
+
+// requiredIn reports whether the key must be defined in the named environment.
+func (k SchemaKey) requiredIn(environment string) bool {
+	return len(k.Environments) == 0 || slices.Contains(k.Environments, environment)
+}
+
+func GetProjectSchema(c echo.Context, ctx *HandlerContext) error {
+	resources, err := GetResources(c)
+	if err != nil {
+		return err
+	}
+
+	dbCtx, cancel := GetDBContext()
+	defer cancel()
+
+	keys, err := ctx.Queries.ListProjectSchemaKeys(dbCtx, resources.Project.ID)
+	if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch project schema"))
+	}
+
+	resp := ProjectSchemaResponse{Keys: []SchemaKey{}}
+	for _, k := range keys {
+		resp.Keys = append(resp.Keys, newSchemaKey(k))
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// UpdateProjectSchema replaces the project's schema with the submitted keys in
+// a single transaction.
+func UpdateProjectSchema(c echo.Context, ctx *HandlerContext) error {
+	resources, err := GetResources(c)
+	if err != nil {
+		return err
+	}
+	claims, err := GetUserOrUnauthorized(c)
+	if err != nil {
+		return err
+	}
+
+	var req ProjectSchemaRequest
+	if err := BindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(req.Keys))
+	for i, k := range req.Keys {
+		if seen[k.Key] {
+			return SendErrorResponse(c, http.StatusBadRequest, fmt.Errorf("key %s appears more than once in the schema", k.Key))
+		}
+		seen[k.Key] = true
+		if k.Type == "" {
+			req.Keys[i].Type = utils.VariableTypeString
+		}
+	}
+	sort.Slice(req.Keys, func(i, j int) bool { return req.Keys[i].Key < req.Keys[j].Key })
+
+	dbCtx, cancel := GetDBContext()
+	defer cancel()
+
+	projectID := resources.Project.ID
+	err = ctx.Tx.ExecTx(dbCtx, func(q database.Querier) error {
+		if err := q.DeleteProjectSchemaKeys(dbCtx, projectID); err != nil {
+			return err
+		}
+		now := time.Now()
+		for _, k := range req.Keys {
+			environments := strings.Join(k.Environments, ",")
+			_, err := q.CreateProjectSchemaKey(dbCtx, database.CreateProjectSchemaKeyParams{
+				ID:           utils.GenerateUUID(),
+				ProjectID:    projectID,
+				Key:          k.Key,
+				Type:         k.Type,
+				Description:  sql.NullString{String: k.Description, Valid: k.Description != ""},
+				Environments: sql.NullString{String: environments, Valid: environments != ""},
+				CreatedAt:    sql.NullTime{Time: now, Valid: true},
+				UpdatedAt:    sql.NullTime{Time: now, Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to update project schema"))
+	}
+
+	details := fmt.Sprintf("%d key(s)", len(req.Keys))
+	if err := recordAuditLog(dbCtx, ctx, projectID, claims.UserID, AuditProjectSchemaUpdated, details); err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to record audit log"))
+	}
+
+	resp := ProjectSchemaResponse{Keys: req.Keys}
+	if resp.Keys == nil {
+		resp.Keys = []SchemaKey{}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// ValidateProjectSchema checks every environment of the project, or only the
+// one named by ?environment=, against the schema. Variables inherited from
+// parent environments and the project count as defined. Values of keys that
+// are present are checked against the type declared in the schema.
+func ValidateProjectSchema(c echo.Context, ctx *HandlerContext) error {
+	resources, err := GetResources(c)
+	if err != nil {
+		return err
+	}
+
+	dbCtx, cancel := GetDBContext()
+	defer cancel()
+
+	projectID := resources.Project.ID
+	schemaKeys, err := ctx.Queries.ListProjectSchemaKeys(dbCtx, projectID)
+	if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch project schema"))
+	}
+
+	environments, err := ctx.Queries.ListEnvironmentsByProject(dbCtx, projectID)
+	if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch environments"))
+	}
+	if ref := c.QueryParam("environment"); ref != "" {
+		environment, err := findProjectEnvironment(dbCtx, ctx, projectID, ref)
+		if err != nil {
+			return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch environments"))
+		}
+		if environment == nil {
+			return SendErrorResponse(c, http.StatusNotFound, fmt.Errorf("environment %s not found", ref))
+		}
+		environments = []database.Environment{*environment}
+	}
+
+	resp := ValidateProjectSchemaResponse{Valid: true, Environments: []EnvironmentSchemaReport{}}
+	for _, environment := range environments {
+		variables, err := resolveEnvironmentVariables(dbCtx, ctx.Queries, environment)
+		if err != nil {
+			return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch environment variables"))
+		}
+		values := make(map[string]string, len(variables))
+		for _, v := range variables {
+			values[v.Key] = v.Value
+		}
+
+		report := EnvironmentSchemaReport{
+			Environment: EnvironmentRef{ID: shared.EnvironmentID(environment.ID), Name: environment.Name},
+			Valid:       true,
+			Missing:     []string{},
+			Invalid:     []SchemaViolation{},
+		}
+		for _, k := range schemaKeys {
+			key := newSchemaKey(k)
+			value, ok := values[key.Key]
+			if !ok {
+				if key.requiredIn(environment.Name) {
+					report.Missing = append(report.Missing, key.Key)
+				}
+				continue
+			}
+			if err := utils.ValidateVariableValue(key.Type, nil, value); err != nil {
+				report.Invalid = append(report.Invalid, SchemaViolation{Key: key.Key, Error: err.Error()})
+			}
+		}
+		report.Valid = len(report.Missing) == 0 && len(report.Invalid) == 0
+		if !report.Valid {
+			resp.Valid = false
+		}
+		resp.Environments = append(resp.Environments, report)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
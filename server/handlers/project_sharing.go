@@ -12,48 +12,75 @@ import (
 	shared "ytsruh.com/envoy/shared"
 )
 
+// ElevationDuration is how long a break-glass elevation grants editor access.
+const ElevationDuration = time.Hour
+
 type AddUserRequest struct {
-	UserID string `json:"user_id" validate:"required"`
-	Role   string `json:"role" validate:"required,oneof=viewer editor"`
+	UserID    string `json:"user_id" validate:"required"`
+	Role      string `json:"role" validate:"required,oneof=metadata viewer editor"`
+	ExpiresIn string `json:"expires_in" validate:"omitempty,duration"`
 }
 
 type UpdateRoleRequest struct {
-	Role string `json:"role" validate:"required,oneof=viewer editor"`
+	Role      string `json:"role" validate:"required,oneof=metadata viewer editor"`
+	ExpiresIn string `json:"expires_in" validate:"omitempty,duration"`
+}
+
+type ElevateAccessRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
 }
 
 type ProjectUserResponse struct {
-	UserID    string    `json:"user_id"`
-	Role      string    `json:"role"`
-	CreatedAt time.Time `json:"created_at"`
+	UserID        string     `json:"user_id"`
+	Role          string     `json:"role"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+	ElevatedUntil *time.Time `json:"elevated_until"`
+	CreatedAt     time.Time  `json:"created_at"`
 }
 
-func AddUserToProject(c echo.Context, ctx *HandlerContext) error {
-	projectID := c.Param("id")
+func newProjectUserResponse(pu database.ProjectUser) ProjectUserResponse {
+	resp := ProjectUserResponse{
+		UserID:    pu.UserID,
+		Role:      pu.Role,
+		CreatedAt: pu.CreatedAt.Time,
+	}
+	if pu.ExpiresAt.Valid {
+		resp.ExpiresAt = &pu.ExpiresAt.Time
+	}
+	if pu.ElevatedUntil.Valid && pu.ElevatedUntil.Time.After(time.Now()) {
+		resp.ElevatedUntil = &pu.ElevatedUntil.Time
+	}
+	return resp
+}
 
+// membershipExpiry converts an optional expires_in duration into an absolute
+// expiry time. An empty value means the membership never expires.
+func membershipExpiry(expiresIn string) sql.NullTime {
+	if expiresIn == "" {
+		return sql.NullTime{}
+	}
+	d, err := utils.ParseDuration(expiresIn)
+	if err != nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: time.Now().Add(d), Valid: true}
+}
+
+func AddUserToProject(c echo.Context, ctx *HandlerContext) error {
 	var req AddUserRequest
 	if err := BindAndValidate(c, &req); err != nil {
 		return err
 	}
 
-	claims, err := GetUserOrUnauthorized(c)
+	resources, err := GetResources(c)
 	if err != nil {
 		return err
 	}
+	projectID := resources.Project.ID
 
 	dbCtx, cancel := GetDBContext()
 	defer cancel()
 
-	ownerCount, err := ctx.Queries.IsProjectOwner(dbCtx, database.IsProjectOwnerParams{
-		ID:      projectID,
-		OwnerID: claims.UserID,
-	})
-	if err != nil {
-		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to check ownership"))
-	}
-	if ownerCount == 0 {
-		return SendErrorResponse(c, http.StatusForbidden, fmt.Errorf("only project owners can add users"))
-	}
-
 	_, err = ctx.Queries.GetUser(dbCtx, req.UserID)
 	if err == sql.ErrNoRows {
 		return SendErrorResponse(c, http.StatusNotFound, fmt.Errorf("user not found"))
@@ -67,47 +94,30 @@ func AddUserToProject(c echo.Context, ctx *HandlerContext) error {
 		ProjectID: projectID,
 		UserID:    req.UserID,
 		Role:      req.Role,
+		ExpiresAt: membershipExpiry(req.ExpiresIn),
 	})
 	if err != nil {
 		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to add user to project"))
 	}
 
-	resp := ProjectUserResponse{
-		UserID:    projectUser.UserID,
-		Role:      projectUser.Role,
-		CreatedAt: projectUser.CreatedAt.Time,
-	}
-
-	return c.JSON(http.StatusCreated, resp)
+	return c.JSON(http.StatusCreated, newProjectUserResponse(projectUser))
 }
 
 func RemoveUserFromProject(c echo.Context, ctx *HandlerContext) error {
-	projectID := c.Param("id")
-
 	userID := c.Param("user_id")
 	if userID == "" {
 		return SendErrorResponse(c, http.StatusBadRequest, fmt.Errorf("invalid user ID"))
 	}
 
-	claims, err := GetUserOrUnauthorized(c)
+	resources, err := GetResources(c)
 	if err != nil {
 		return err
 	}
+	projectID := resources.Project.ID
 
 	dbCtx, cancel := GetDBContext()
 	defer cancel()
 
-	ownerCount, err := ctx.Queries.IsProjectOwner(dbCtx, database.IsProjectOwnerParams{
-		ID:      projectID,
-		OwnerID: claims.UserID,
-	})
-	if err != nil {
-		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to check ownership"))
-	}
-	if ownerCount == 0 {
-		return SendErrorResponse(c, http.StatusForbidden, fmt.Errorf("only project owners can remove users"))
-	}
-
 	err = ctx.Queries.RemoveUserFromProject(dbCtx, database.RemoveUserFromProjectParams{
 		ProjectID: projectID,
 		UserID:    userID,
@@ -120,8 +130,6 @@ func RemoveUserFromProject(c echo.Context, ctx *HandlerContext) error {
 }
 
 func UpdateUserRole(c echo.Context, ctx *HandlerContext) error {
-	projectID := c.Param("id")
-
 	userID := c.Param("user_id")
 	if userID == "" {
 		return SendErrorResponse(c, http.StatusBadRequest, fmt.Errorf("invalid user ID"))
@@ -132,29 +140,21 @@ func UpdateUserRole(c echo.Context, ctx *HandlerContext) error {
 		return err
 	}
 
-	claims, err := GetUserOrUnauthorized(c)
+	resources, err := GetResources(c)
 	if err != nil {
 		return err
 	}
+	projectID := resources.Project.ID
 
 	dbCtx, cancel := GetDBContext()
 	defer cancel()
 
-	ownerCount, err := ctx.Queries.IsProjectOwner(dbCtx, database.IsProjectOwnerParams{
-		ID:      projectID,
-		OwnerID: claims.UserID,
-	})
-	if err != nil {
-		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to check ownership"))
-	}
-	if ownerCount == 0 {
-		return SendErrorResponse(c, http.StatusForbidden, fmt.Errorf("only project owners can update user roles"))
-	}
-
 	err = ctx.Queries.UpdateUserRole(dbCtx, database.UpdateUserRoleParams{
 		ProjectID: projectID,
 		UserID:    userID,
 		Role:      req.Role,
+		ExpiresAt: membershipExpiry(req.ExpiresIn),
+		UpdatedAt: time.Now(),
 	})
 	if err != nil {
 		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to update user role"))
@@ -164,32 +164,80 @@ func UpdateUserRole(c echo.Context, ctx *HandlerContext) error {
 }
 
 func GetProjectUsers(c echo.Context, ctx *HandlerContext) error {
-	projectID := c.Param("id")
-
-	if _, err := GetUserOrUnauthorized(c); err != nil {
+	resources, err := GetResources(c)
+	if err != nil {
 		return err
 	}
 
 	dbCtx, cancel := GetDBContext()
 	defer cancel()
 
-	projectUsers, err := ctx.Queries.GetProjectUsers(dbCtx, projectID)
+	projectUsers, err := ctx.Queries.GetProjectUsers(dbCtx, resources.Project.ID)
 	if err != nil {
 		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch project users"))
 	}
 
 	var resp []ProjectUserResponse
 	for _, pu := range projectUsers {
-		resp = append(resp, ProjectUserResponse{
-			UserID:    pu.UserID,
-			Role:      pu.Role,
-			CreatedAt: pu.CreatedAt.Time,
-		})
+		resp = append(resp, newProjectUserResponse(pu))
 	}
 
 	return c.JSON(http.StatusOK, resp)
 }
 
+// ElevateProjectAccess grants a viewer temporary editor access for
+// ElevationDuration. The reason is recorded in the project audit log.
+func ElevateProjectAccess(c echo.Context, ctx *HandlerContext) error {
+	var req ElevateAccessRequest
+	if err := BindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	claims, err := GetUserOrUnauthorized(c)
+	if err != nil {
+		return err
+	}
+
+	resources, err := GetResources(c)
+	if err != nil {
+		return err
+	}
+	projectID := resources.Project.ID
+
+	if resources.Role != utils.RoleViewer {
+		return SendErrorResponse(c, http.StatusBadRequest, fmt.Errorf("only viewers can request elevated access"))
+	}
+
+	dbCtx, cancel := GetDBContext()
+	defer cancel()
+
+	now := time.Now()
+	elevatedUntil := now.Add(ElevationDuration)
+	err = ctx.Tx.ExecTx(dbCtx, func(q database.Querier) error {
+		err := q.ElevateProjectUser(dbCtx, database.ElevateProjectUserParams{
+			ElevatedUntil: sql.NullTime{Time: elevatedUntil, Valid: true},
+			UpdatedAt:     now,
+			ProjectID:     projectID,
+			UserID:        claims.UserID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to elevate access")
+		}
+		if err := writeAuditLog(dbCtx, q, projectID, claims.UserID, AuditMembershipElevated, req.Reason); err != nil {
+			return fmt.Errorf("failed to record audit log")
+		}
+		return nil
+	})
+	if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"role":           utils.RoleEditor,
+		"elevated_until": elevatedUntil,
+	})
+}
+
 func ListUserProjects(c echo.Context, ctx *HandlerContext) error {
 	claims, err := GetUserOrUnauthorized(c)
 	if err != nil {
@@ -202,6 +250,7 @@ func ListUserProjects(c echo.Context, ctx *HandlerContext) error {
 	projects, err := ctx.Queries.GetUserProjects(dbCtx, database.GetUserProjectsParams{
 		OwnerID: claims.UserID,
 		UserID:  claims.UserID,
+		Now:     sql.NullTime{Time: time.Now(), Valid: true},
 	})
 	if err != nil {
 		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch projects"))
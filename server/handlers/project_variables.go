@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	database "ytsruh.com/envoy/server/database/generated"
+	"ytsruh.com/envoy/server/utils"
+	shared "ytsruh.com/envoy/shared"
+)
+
+type CreateProjectVariableRequest struct {
+	Key         string `json:"key" validate:"required"`
+	Value       string `json:"value" validate:"required"`
+	Description string `json:"description" validate:"max=500"`
+}
+
+type UpdateProjectVariableRequest struct {
+	Key         string `json:"key" validate:"required"`
+	Value       string `json:"value" validate:"required"`
+	Description string `json:"description" validate:"max=500"`
+}
+
+type ProjectVariableResponse struct {
+	ID          shared.ProjectVariableID `json:"id"`
+	ProjectID   shared.ProjectID         `json:"project_id"`
+	Key         string                   `json:"key"`
+	Value       string                   `json:"value"`
+	Description *string                  `json:"description"`
+	Redacted    bool                     `json:"redacted,omitempty"`
+	Masked      bool                     `json:"masked,omitempty"`
+	CreatedAt   shared.Timestamp         `json:"created_at"`
+	UpdatedAt   shared.Timestamp         `json:"updated_at"`
+}
+
+func newProjectVariableResponse(v database.ProjectVariable) ProjectVariableResponse {
+	return ProjectVariableResponse{
+		ID:          shared.ProjectVariableID(v.ID),
+		ProjectID:   shared.ProjectID(v.ProjectID),
+		Key:         v.Key,
+		Value:       v.Value,
+		Description: shared.NullStringToStringPtr(v.Description),
+		CreatedAt:   shared.FromTime(v.CreatedAt.Time),
+		UpdatedAt:   shared.FromTime(v.UpdatedAt.Time),
+	}
+}
+
+// maskProjectValue applies the same masking rules as maskValue.
+func maskProjectValue(resp *ProjectVariableResponse, role string, reveal bool) {
+	switch {
+	case role == utils.RoleMetadata:
+		resp.Value = ""
+		resp.Redacted = true
+	case !reveal:
+		resp.Value = ""
+		resp.Masked = true
+	}
+}
+
+func CreateProjectVariable(c echo.Context, ctx *HandlerContext) error {
+	resources, err := GetResources(c)
+	if err != nil {
+		return err
+	}
+
+	var req CreateProjectVariableRequest
+	if err := BindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	dbCtx, cancel := GetDBContext()
+	defer cancel()
+
+	_, err = ctx.Queries.GetProjectVariableByKey(dbCtx, database.GetProjectVariableByKeyParams{
+		ProjectID: resources.Project.ID,
+		Key:       req.Key,
+	})
+	if err == nil {
+		return SendErrorResponse(c, http.StatusConflict, fmt.Errorf("a variable with this key already exists in this project"))
+	}
+
+	now := time.Now()
+	variable, err := ctx.Queries.CreateProjectVariable(dbCtx, database.CreateProjectVariableParams{
+		ID:          utils.GenerateUUID(),
+		ProjectID:   resources.Project.ID,
+		Key:         req.Key,
+		Value:       req.Value,
+		Description: sql.NullString{String: req.Description, Valid: req.Description != ""},
+		CreatedAt:   sql.NullTime{Time: now, Valid: true},
+		UpdatedAt:   sql.NullTime{Time: now, Valid: true},
+	})
+	if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to create project variable"))
+	}
+
+	return c.JSON(http.StatusCreated, newProjectVariableResponse(variable))
+}
+
+// ListProjectVariables returns the variables shared by every environment of a
+// project. Values are masked; passing ?reveal=true returns plaintext values and
+// is audited.
+func ListProjectVariables(c echo.Context, ctx *HandlerContext) error {
+	resources, err := GetResources(c)
+	if err != nil {
+		return err
+	}
+	claims, err := GetUserOrUnauthorized(c)
+	if err != nil {
+		return err
+	}
+
+	reveal := c.QueryParam("reveal") == "true"
+	if reveal && !utils.RoleAtLeast(resources.Role, utils.RoleViewer) {
+		return SendErrorResponse(c, http.StatusForbidden, fmt.Errorf("insufficient permissions to reveal variable values"))
+	}
+
+	dbCtx, cancel := GetDBContext()
+	defer cancel()
+
+	variables, err := ctx.Queries.ListProjectVariablesByProject(dbCtx, resources.Project.ID)
+	if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch project variables"))
+	}
+
+	if reveal {
+		details := fmt.Sprintf("project: %d variable(s)", len(variables))
+		if err := recordAuditLog(dbCtx, ctx, resources.Project.ID, claims.UserID, AuditVariablesRevealed, details); err != nil {
+			return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to record audit log"))
+		}
+	}
+
+	resp := []ProjectVariableResponse{}
+	for _, v := range variables {
+		item := newProjectVariableResponse(v)
+		maskProjectValue(&item, resources.Role, reveal)
+		resp = append(resp, item)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+func GetProjectVariable(c echo.Context, ctx *HandlerContext) error {
+	resources, err := GetResources(c)
+	if err != nil {
+		return err
+	}
+
+	resp := newProjectVariableResponse(*resources.ProjectVariable)
+	maskProjectValue(&resp, resources.Role, false)
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// RevealProjectVariable returns a single project variable with its plaintext
+// value and records the reveal in the project's audit log.
+func RevealProjectVariable(c echo.Context, ctx *HandlerContext) error {
+	resources, err := GetResources(c)
+	if err != nil {
+		return err
+	}
+	claims, err := GetUserOrUnauthorized(c)
+	if err != nil {
+		return err
+	}
+
+	dbCtx, cancel := GetDBContext()
+	defer cancel()
+
+	variable := resources.ProjectVariable
+	details := fmt.Sprintf("project/%s", variable.Key)
+	if err := recordAuditLog(dbCtx, ctx, resources.Project.ID, claims.UserID, AuditVariableRevealed, details); err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to record audit log"))
+	}
+
+	return c.JSON(http.StatusOK, newProjectVariableResponse(*variable))
+}
+
+func UpdateProjectVariable(c echo.Context, ctx *HandlerContext) error {
+	resources, err := GetResources(c)
+	if err != nil {
+		return err
+	}
+
+	var req UpdateProjectVariableRequest
+	if err := BindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	dbCtx, cancel := GetDBContext()
+	defer cancel()
+
+	if req.Key != resources.ProjectVariable.Key {
+		_, err := ctx.Queries.GetProjectVariableByKey(dbCtx, database.GetProjectVariableByKeyParams{
+			ProjectID: resources.Project.ID,
+			Key:       req.Key,
+		})
+		if err == nil {
+			return SendErrorResponse(c, http.StatusConflict, fmt.Errorf("a variable with this key already exists in this project"))
+		}
+	}
+
+	variable, err := ctx.Queries.UpdateProjectVariable(dbCtx, database.UpdateProjectVariableParams{
+		Key:         req.Key,
+		Value:       req.Value,
+		Description: sql.NullString{String: req.Description, Valid: req.Description != ""},
+		UpdatedAt:   sql.NullTime{Time: time.Now(), Valid: true},
+		ID:          resources.ProjectVariable.ID,
+	})
+	if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to update project variable"))
+	}
+
+	return c.JSON(http.StatusOK, newProjectVariableResponse(variable))
+}
+
+func DeleteProjectVariable(c echo.Context, ctx *HandlerContext) error {
+	resources, err := GetResources(c)
+	if err != nil {
+		return err
+	}
+
+	dbCtx, cancel := GetDBContext()
+	defer cancel()
+
+	if err := ctx.Queries.DeleteProjectVariable(dbCtx, resources.ProjectVariable.ID); err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to delete project variable"))
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "Project variable deleted successfully"})
+}
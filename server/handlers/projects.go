@@ -87,26 +87,11 @@ func CreateProject(c echo.Context, ctx *HandlerContext) error {
 }
 
 func GetProject(c echo.Context, ctx *HandlerContext) error {
-	projectID := c.Param("id")
-
-	claims, err := GetUserOrUnauthorized(c)
+	resources, err := GetResources(c)
 	if err != nil {
 		return err
 	}
-
-	dbCtx, cancel := GetDBContext()
-	defer cancel()
-
-	project, err := ctx.Queries.GetAccessibleProject(dbCtx, database.GetAccessibleProjectParams{
-		ID:      projectID,
-		OwnerID: claims.UserID,
-		UserID:  claims.UserID,
-	})
-	if err == sql.ErrNoRows {
-		return SendErrorResponse(c, http.StatusNotFound, fmt.Errorf("project not found or access denied"))
-	} else if err != nil {
-		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch project"))
-	}
+	project := resources.Project
 
 	resp := ProjectResponse{
 		ID:          project.ID,
@@ -133,6 +118,7 @@ func ListProjects(c echo.Context, ctx *HandlerContext) error {
 	projects, err := ctx.Queries.GetUserProjects(dbCtx, database.GetUserProjectsParams{
 		OwnerID: claims.UserID,
 		UserID:  claims.UserID,
+		Now:     sql.NullTime{Time: time.Now(), Valid: true},
 	})
 	if err != nil {
 		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch projects"))
@@ -155,40 +141,20 @@ func ListProjects(c echo.Context, ctx *HandlerContext) error {
 }
 
 func UpdateProject(c echo.Context, ctx *HandlerContext) error {
-	projectID := c.Param("id")
-
 	var req UpdateProjectRequest
 	if err := BindAndValidate(c, &req); err != nil {
 		return err
 	}
 
-	claims, err := GetUserOrUnauthorized(c)
+	resources, err := GetResources(c)
 	if err != nil {
 		return err
 	}
+	originalProject := resources.Project
 
 	dbCtx, cancel := GetDBContext()
 	defer cancel()
 
-	canModify, err := ctx.Queries.CanUserModifyProject(dbCtx, database.CanUserModifyProjectParams{
-		ID:      projectID,
-		OwnerID: claims.UserID,
-		UserID:  claims.UserID,
-	})
-	if err != nil {
-		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to check permissions"))
-	}
-	if canModify == 0 {
-		return SendErrorResponse(c, http.StatusForbidden, fmt.Errorf("access denied"))
-	}
-
-	originalProject, err := ctx.Queries.GetProject(dbCtx, projectID)
-	if err == sql.ErrNoRows {
-		return SendErrorResponse(c, http.StatusNotFound, fmt.Errorf("project not found"))
-	} else if err != nil {
-		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch project"))
-	}
-
 	if req.GitRepo != "" && (NullStringToString(originalProject.GitRepo) != req.GitRepo) {
 		_, err := ctx.Queries.GetProjectByGitRepo(dbCtx, database.GetProjectByGitRepoParams{
 			OwnerID: originalProject.OwnerID,
@@ -205,7 +171,7 @@ func UpdateProject(c echo.Context, ctx *HandlerContext) error {
 		Description: sql.NullString{String: req.Description, Valid: req.Description != ""},
 		GitRepo:     sql.NullString{String: req.GitRepo, Valid: req.GitRepo != ""},
 		UpdatedAt:   now,
-		ID:          projectID,
+		ID:          originalProject.ID,
 		OwnerID:     originalProject.OwnerID,
 	})
 
@@ -223,9 +189,7 @@ func UpdateProject(c echo.Context, ctx *HandlerContext) error {
 }
 
 func DeleteProject(c echo.Context, ctx *HandlerContext) error {
-	projectID := c.Param("id")
-
-	claims, err := GetUserOrUnauthorized(c)
+	resources, err := GetResources(c)
 	if err != nil {
 		return err
 	}
@@ -233,21 +197,10 @@ func DeleteProject(c echo.Context, ctx *HandlerContext) error {
 	dbCtx, cancel := GetDBContext()
 	defer cancel()
 
-	ownerCount, err := ctx.Queries.IsProjectOwner(dbCtx, database.IsProjectOwnerParams{
-		ID:      projectID,
-		OwnerID: claims.UserID,
-	})
-	if err != nil {
-		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to check ownership"))
-	}
-	if ownerCount == 0 {
-		return SendErrorResponse(c, http.StatusForbidden, fmt.Errorf("only project owners can delete projects"))
-	}
-
 	err = ctx.Queries.DeleteProject(dbCtx, database.DeleteProjectParams{
 		DeletedAt: sql.NullTime{Time: time.Now(), Valid: true},
-		ID:        projectID,
-		OwnerID:   claims.UserID,
+		ID:        resources.Project.ID,
+		OwnerID:   resources.Project.OwnerID,
 	})
 	if err != nil {
 		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to delete project"))
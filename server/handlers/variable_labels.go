@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	database "ytsruh.com/envoy/server/database/generated"
+	"ytsruh.com/envoy/server/utils"
+)
+
+// VariableLabelsRequest replaces every label of a variable. An empty map
+// removes them all.
+type VariableLabelsRequest struct {
+	Labels map[string]string `json:"labels" validate:"labels"`
+}
+
+// labelsColumn encodes labels for storage, storing NULL when there are none.
+func labelsColumn(labels map[string]string) sql.NullString {
+	formatted := utils.FormatLabels(labels)
+	return sql.NullString{String: formatted, Valid: formatted != ""}
+}
+
+// filterByLabels keeps the variables whose labels match every selector.
+func filterByLabels(variables []resolvedVariable, selectors []string) []resolvedVariable {
+	var matched []resolvedVariable
+	for _, v := range variables {
+		if utils.MatchLabels(utils.ParseLabels(v.Labels.String), selectors) {
+			matched = append(matched, v)
+		}
+	}
+	return matched
+}
+
+// SetEnvironmentVariableLabels replaces the labels of a variable.
+func SetEnvironmentVariableLabels(c echo.Context, ctx *HandlerContext) error {
+	resources, err := GetResources(c)
+	if err != nil {
+		return err
+	}
+
+	var req VariableLabelsRequest
+	if err := BindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	dbCtx, cancel := GetDBContext()
+	defer cancel()
+
+	variable, err := ctx.Queries.UpdateEnvironmentVariableLabels(dbCtx, database.UpdateEnvironmentVariableLabelsParams{
+		Labels:    labelsColumn(req.Labels),
+		UpdatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		ID:        resources.Variable.ID,
+	})
+	if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to update variable labels"))
+	}
+
+	resp := newEnvironmentVariableResponse(variable)
+	maskValue(&resp, resources.Role, false)
+
+	return c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	database "ytsruh.com/envoy/server/database/generated"
+	"ytsruh.com/envoy/server/utils"
+)
+
+const (
+	AuditVariableValuesReplaced = "variables.value_replaced"
+)
+
+// ReplaceValueRequest replaces a value wherever it is used. Fingerprint is the
+// SHA-256 of the old value, as used by SearchVariables, so the old value never
+// has to be sent. File variables are given the new value as their contents.
+type ReplaceValueRequest struct {
+	Fingerprint string `json:"fingerprint" validate:"required,fingerprint"`
+	Value       string `json:"value" validate:"required"`
+	DryRun      bool   `json:"dry_run"`
+}
+
+// ReplaceValueResponse lists the variables whose value was replaced, or would
+// be with DryRun, and those that hold the old value but were skipped because
+// the user cannot edit their project.
+type ReplaceValueResponse struct {
+	DryRun   bool                   `json:"dry_run"`
+	Replaced []VariableSearchResult `json:"replaced"`
+	Skipped  []VariableSearchResult `json:"skipped"`
+}
+
+// replacedLocation describes where a value was replaced for the audit log.
+func replacedLocation(v VariableSearchResult) string {
+	if v.Environment != nil {
+		return v.Environment.Name + "/" + v.Key
+	}
+	return "project/" + v.Key
+}
+
+// ReplaceValue replaces a leaked value in every variable that holds it, across
+// every project the user can access. All variables are updated in a single
+// transaction along with an audit entry in each affected project, so either
+// every editable copy of the value is replaced or none is. Projects the user
+// cannot edit are reported as skipped. The new value is checked against the
+// type of every matched variable before anything is written; with dry_run the
+// matches are listed without writing. Replaced environment variables count as
+// rotated and lose the old value's expiry.
+func ReplaceValue(c echo.Context, ctx *HandlerContext) error {
+	claims, err := GetUserOrUnauthorized(c)
+	if err != nil {
+		return err
+	}
+
+	var req ReplaceValueRequest
+	if err := BindAndValidate(c, &req); err != nil {
+		return err
+	}
+	fingerprint := strings.ToLower(req.Fingerprint)
+	if utils.ValueFingerprint(req.Value, utils.VariableTypeString) == fingerprint {
+		return SendErrorResponse(c, http.StatusBadRequest, fmt.Errorf("the new value must differ from the old value"))
+	}
+
+	dbCtx, cancel := GetDBContext()
+	defer cancel()
+
+	resp := ReplaceValueResponse{
+		DryRun:   req.DryRun,
+		Replaced: []VariableSearchResult{},
+		Skipped:  []VariableSearchResult{},
+	}
+	var invalid error
+	err = ctx.Tx.ExecTx(dbCtx, func(q database.Querier) error {
+		found, err := findVariables(dbCtx, q, claims.UserID, "", fingerprint)
+		if err != nil {
+			return fmt.Errorf("failed to fetch variables")
+		}
+
+		now := time.Now()
+		replaced := make(map[string][]string)
+		var projectIDs []string
+		for _, match := range found {
+			if !utils.RoleAtLeast(match.Role, utils.RoleEditor) {
+				resp.Skipped = append(resp.Skipped, match.VariableSearchResult)
+				continue
+			}
+
+			if match.Environment == nil {
+				variable, err := q.GetProjectVariable(dbCtx, match.ID)
+				if err != nil {
+					return fmt.Errorf("failed to fetch %s", match.Key)
+				}
+				if !req.DryRun {
+					_, err = q.UpdateProjectVariable(dbCtx, database.UpdateProjectVariableParams{
+						Key:         variable.Key,
+						Value:       req.Value,
+						Description: variable.Description,
+						UpdatedAt:   sql.NullTime{Time: now, Valid: true},
+						ID:          variable.ID,
+					})
+					if err != nil {
+						return fmt.Errorf("failed to update %s", match.Key)
+					}
+				}
+			} else {
+				variable, err := q.GetEnvironmentVariable(dbCtx, match.ID)
+				if err != nil {
+					return fmt.Errorf("failed to fetch %s", match.Key)
+				}
+				value := req.Value
+				if variable.Type == utils.VariableTypeFile {
+					value = base64.StdEncoding.EncodeToString([]byte(req.Value))
+				}
+				if err := validateExistingValue(variable, value); err != nil {
+					invalid = fmt.Errorf("%s/%s: %v", match.Project.Name, match.Environment.Name, err)
+					return invalid
+				}
+				if !req.DryRun {
+					_, err = q.UpdateEnvironmentVariable(dbCtx, database.UpdateEnvironmentVariableParams{
+						Key:           variable.Key,
+						Value:         value,
+						Description:   variable.Description,
+						UpdatedAt:     sql.NullTime{Time: now, Valid: true},
+						Type:          variableType(variable),
+						AllowedValues: variable.AllowedValues,
+						RotatedAt:     sql.NullTime{Time: now, Valid: true},
+						Filename:      variable.Filename,
+						FileMode:      variable.FileMode,
+						Labels:        variable.Labels,
+						ID:            variable.ID,
+					})
+					if err != nil {
+						return fmt.Errorf("failed to update %s", match.Key)
+					}
+				}
+			}
+
+			resp.Replaced = append(resp.Replaced, match.VariableSearchResult)
+			projectID := string(match.Project.ID)
+			if _, ok := replaced[projectID]; !ok {
+				projectIDs = append(projectIDs, projectID)
+			}
+			replaced[projectID] = append(replaced[projectID], replacedLocation(match.VariableSearchResult))
+		}
+
+		if req.DryRun {
+			return nil
+		}
+		for _, projectID := range projectIDs {
+			details := fmt.Sprintf("value fingerprint %s replaced in %s", fingerprint[:12], strings.Join(replaced[projectID], ", "))
+			if err := writeAuditLog(dbCtx, q, projectID, claims.UserID, AuditVariableValuesReplaced, details); err != nil {
+				return fmt.Errorf("failed to record audit log")
+			}
+		}
+		return nil
+	})
+	if invalid != nil {
+		return SendErrorResponse(c, http.StatusBadRequest, fmt.Errorf("new value rejected and no changes were applied: %v", invalid))
+	}
+	if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("replacement failed and no changes were applied: %v", err))
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	database "ytsruh.com/envoy/server/database/generated"
+	"ytsruh.com/envoy/server/utils"
+	shared "ytsruh.com/envoy/shared"
+)
+
+// Statuses of variables returned by ListStaleVariables, most urgent first.
+const (
+	StaleStatusExpired         = "expired"
+	StaleStatusRotationOverdue = "rotation_overdue"
+	StaleStatusExpiring        = "expiring"
+	StaleStatusRotationDue     = "rotation_due"
+)
+
+// defaultStaleWindow is how far ahead ListStaleVariables looks for upcoming
+// expiries and rotations when ?within= is not given.
+const defaultStaleWindow = 14 * 24 * time.Hour
+
+// VariableRotationRequest replaces the rotation metadata of a variable.
+// RotateAfter is how long a value may be used before it must be rotated, such
+// as "90d"; ExpiresAt is when the current value stops working. Empty values
+// clear them.
+type VariableRotationRequest struct {
+	RotateAfter string           `json:"rotate_after" validate:"omitempty,duration"`
+	ExpiresAt   shared.Timestamp `json:"expires_at"`
+}
+
+type ProjectRef struct {
+	ID   shared.ProjectID `json:"id"`
+	Name string           `json:"name"`
+}
+
+type StaleVariableResponse struct {
+	ID          shared.EnvironmentVariableID `json:"id"`
+	Key         string                       `json:"key"`
+	Type        string                       `json:"type"`
+	Project     ProjectRef                   `json:"project"`
+	Environment EnvironmentRef               `json:"environment"`
+	Status      string                       `json:"status"`
+	DueAt       shared.Timestamp             `json:"due_at"`
+	RotateAfter string                       `json:"rotate_after,omitempty"`
+	RotatedAt   shared.Timestamp             `json:"rotated_at"`
+	ExpiresAt   shared.Timestamp             `json:"expires_at"`
+}
+
+// rotationColumns converts requested rotation metadata into the stored columns.
+func rotationColumns(rotateAfter string, expiresAt shared.Timestamp) (sql.NullString, sql.NullTime) {
+	return sql.NullString{String: rotateAfter, Valid: rotateAfter != ""},
+		sql.NullTime{Time: expiresAt.ToTime(), Valid: !expiresAt.ToTime().IsZero()}
+}
+
+// rotationDeadline returns when a value must next be rotated: rotateAfter after
+// it was last changed. ok is false when the variable has no rotation interval.
+func rotationDeadline(rotateAfter sql.NullString, rotatedAt, updatedAt sql.NullTime) (deadline time.Time, ok bool) {
+	if !rotateAfter.Valid {
+		return time.Time{}, false
+	}
+	interval, err := utils.ParseDuration(rotateAfter.String)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if !rotatedAt.Valid {
+		rotatedAt = updatedAt
+	}
+	return rotatedAt.Time.Add(interval), true
+}
+
+// setRotationStatus fills in the rotation metadata of resp and flags values
+// that have expired or are overdue for rotation.
+func setRotationStatus(resp *EnvironmentVariableResponse, v database.EnvironmentVariable, now time.Time) {
+	resp.RotateAfter = v.RotateAfter.String
+	if deadline, ok := rotationDeadline(v.RotateAfter, v.RotatedAt, v.UpdatedAt); ok {
+		resp.RotateBy = shared.FromTime(deadline)
+		resp.RotationOverdue = !deadline.After(now)
+	}
+	if v.ExpiresAt.Valid {
+		resp.ExpiresAt = shared.FromTime(v.ExpiresAt.Time)
+		resp.Expired = !v.ExpiresAt.Time.After(now)
+	}
+}
+
+// SetEnvironmentVariableRotation replaces how often a variable must be rotated
+// and when its current value expires.
+func SetEnvironmentVariableRotation(c echo.Context, ctx *HandlerContext) error {
+	resources, err := GetResources(c)
+	if err != nil {
+		return err
+	}
+
+	var req VariableRotationRequest
+	if err := BindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	dbCtx, cancel := GetDBContext()
+	defer cancel()
+
+	rotateAfter, expiresAt := rotationColumns(req.RotateAfter, req.ExpiresAt)
+	variable, err := ctx.Queries.UpdateEnvironmentVariableRotation(dbCtx, database.UpdateEnvironmentVariableRotationParams{
+		RotateAfter: rotateAfter,
+		ExpiresAt:   expiresAt,
+		UpdatedAt:   sql.NullTime{Time: time.Now(), Valid: true},
+		ID:          resources.Variable.ID,
+	})
+	if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to update variable rotation"))
+	}
+
+	resp := newEnvironmentVariableResponse(variable)
+	maskValue(&resp, resources.Role, false)
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// ListStaleVariables returns the variables, across every project the user can
+// access, that have expired or are overdue for rotation, along with those that
+// expire or are due for rotation within ?within= (default 14d). Only metadata
+// is returned, never values. Results are ordered by how soon they are due.
+func ListStaleVariables(c echo.Context, ctx *HandlerContext) error {
+	claims, err := GetUserOrUnauthorized(c)
+	if err != nil {
+		return err
+	}
+
+	window := defaultStaleWindow
+	if within := c.QueryParam("within"); within != "" {
+		window, err = utils.ParseDuration(within)
+		if err != nil {
+			return SendErrorResponse(c, http.StatusBadRequest, fmt.Errorf("within must be a duration such as 12h or 30d"))
+		}
+	}
+
+	dbCtx, cancel := GetDBContext()
+	defer cancel()
+
+	now := time.Now()
+	variables, err := ctx.Queries.ListUserRotationVariables(dbCtx, database.ListUserRotationVariablesParams{
+		UserID: claims.UserID,
+		Now:    sql.NullTime{Time: now, Valid: true},
+	})
+	if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch variables"))
+	}
+
+	horizon := now.Add(window)
+	resp := []StaleVariableResponse{}
+	for _, v := range variables {
+		deadline, rotates := rotationDeadline(v.RotateAfter, v.RotatedAt, v.UpdatedAt)
+		expires := v.ExpiresAt.Time
+
+		var status string
+		var due time.Time
+		switch {
+		case v.ExpiresAt.Valid && !expires.After(now):
+			status, due = StaleStatusExpired, expires
+		case rotates && !deadline.After(now):
+			status, due = StaleStatusRotationOverdue, deadline
+		case v.ExpiresAt.Valid && expires.Before(horizon) && (!rotates || expires.Before(deadline)):
+			status, due = StaleStatusExpiring, expires
+		case rotates && deadline.Before(horizon):
+			status, due = StaleStatusRotationDue, deadline
+		default:
+			continue
+		}
+
+		item := StaleVariableResponse{
+			ID:          shared.EnvironmentVariableID(v.ID),
+			Key:         v.Key,
+			Type:        v.Type,
+			Project:     ProjectRef{ID: shared.ProjectID(v.ProjectID), Name: v.ProjectName},
+			Environment: EnvironmentRef{ID: shared.EnvironmentID(v.EnvironmentID), Name: v.EnvironmentName},
+			Status:      status,
+			DueAt:       shared.FromTime(due),
+			RotateAfter: v.RotateAfter.String,
+			RotatedAt:   shared.FromTime(v.RotatedAt.Time),
+		}
+		if v.ExpiresAt.Valid {
+			item.ExpiresAt = shared.FromTime(expires)
+		}
+		resp = append(resp, item)
+	}
+	sort.SliceStable(resp, func(i, j int) bool {
+		return resp[i].DueAt.ToTime().Before(resp[j].DueAt.ToTime())
+	})
+
+	return c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	database "ytsruh.com/envoy/server/database/generated"
+	"ytsruh.com/envoy/server/utils"
+	shared "ytsruh.com/envoy/shared"
+)
+
+const (
+	AuditVariablesSearched = "variables.searched"
+)
+
+// VariableSearchResult is a variable matched by SearchVariables. Origin is
+// "environment" for environment variables and "project" for project-level
+// variables, which have no Environment. Values are never returned.
+type VariableSearchResult struct {
+	ID          string          `json:"id"`
+	Key         string          `json:"key"`
+	Type        string          `json:"type"`
+	Origin      string          `json:"origin"`
+	Project     ProjectRef      `json:"project"`
+	Environment *EnvironmentRef `json:"environment,omitempty"`
+	ValueMatch  bool            `json:"value_match"`
+}
+
+// variableMatch is a variable found by findVariables along with the caller's
+// role on its project.
+type variableMatch struct {
+	VariableSearchResult
+	Role string
+}
+
+// searchRole returns the caller's role on a project from its owner and the
+// caller's membership, treating a temporarily elevated member as an editor.
+func searchRole(ownerID, userID string, memberRole sql.NullString, elevatedUntil sql.NullTime, now time.Time) string {
+	if ownerID == userID {
+		return utils.RoleOwner
+	}
+	if elevatedUntil.Valid && elevatedUntil.Time.After(now) {
+		return utils.RoleEditor
+	}
+	return memberRole.String
+}
+
+// findVariables returns the environment and project variables, across every
+// project the user can access, whose key matches pattern and whose value has
+// fingerprint. Empty criteria match everything. Fingerprints are never compared
+// in projects where the user only has metadata access. Patterns must already
+// be upper case. Results are ordered by project.
+func findVariables(dbCtx context.Context, q database.Querier, userID, pattern, fingerprint string) ([]variableMatch, error) {
+	now := time.Now()
+	environmentVariables, err := q.ListUserEnvironmentVariables(dbCtx, database.ListUserEnvironmentVariablesParams{
+		UserID: userID,
+		Now:    sql.NullTime{Time: now, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	projectVariables, err := q.ListUserProjectVariables(dbCtx, database.ListUserProjectVariablesParams{
+		UserID: userID,
+		Now:    sql.NullTime{Time: now, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// matches reports whether a variable matches the search, and whether its
+	// value matched the fingerprint.
+	matches := func(key, value, variableType, role string) (bool, bool) {
+		if pattern != "" {
+			if ok, _ := path.Match(pattern, strings.ToUpper(key)); !ok {
+				return false, false
+			}
+		}
+		if fingerprint == "" {
+			return true, false
+		}
+		if !utils.RoleAtLeast(role, utils.RoleViewer) {
+			return false, false
+		}
+		valueMatch := utils.ValueFingerprint(value, variableType) == fingerprint
+		return valueMatch, valueMatch
+	}
+
+	var found []variableMatch
+	for _, v := range environmentVariables {
+		role := searchRole(v.OwnerID, userID, v.MemberRole, v.ElevatedUntil, now)
+		ok, valueMatch := matches(v.Key, v.Value, v.Type, role)
+		if !ok {
+			continue
+		}
+		found = append(found, variableMatch{
+			VariableSearchResult: VariableSearchResult{
+				ID:          v.ID,
+				Key:         v.Key,
+				Type:        v.Type,
+				Origin:      "environment",
+				Project:     ProjectRef{ID: shared.ProjectID(v.ProjectID), Name: v.ProjectName},
+				Environment: &EnvironmentRef{ID: shared.EnvironmentID(v.EnvironmentID), Name: v.EnvironmentName},
+				ValueMatch:  valueMatch,
+			},
+			Role: role,
+		})
+	}
+	for _, v := range projectVariables {
+		role := searchRole(v.OwnerID, userID, v.MemberRole, v.ElevatedUntil, now)
+		ok, valueMatch := matches(v.Key, v.Value, utils.VariableTypeString, role)
+		if !ok {
+			continue
+		}
+		found = append(found, variableMatch{
+			VariableSearchResult: VariableSearchResult{
+				ID:         v.ID,
+				Key:        v.Key,
+				Type:       utils.VariableTypeString,
+				Origin:     "project",
+				Project:    ProjectRef{ID: shared.ProjectID(v.ProjectID), Name: v.ProjectName},
+				ValueMatch: valueMatch,
+			},
+			Role: role,
+		})
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].Project.Name < found[j].Project.Name
+	})
+	return found, nil
+}
+
+// SearchVariables finds variables across every project the user can access.
+// ?key= matches keys against a case-insensitive glob pattern such as
+// "*STRIPE*". ?fingerprint= matches the SHA-256 of stored values, so a leaked
+// credential can be traced without revealing any values; references are
+// compared unexpanded. When both are given a variable must match both.
+// Fingerprints are never compared in projects where the user only has
+// metadata access, and fingerprint matches are recorded in the audit log of
+// each project they are found in.
+func SearchVariables(c echo.Context, ctx *HandlerContext) error {
+	claims, err := GetUserOrUnauthorized(c)
+	if err != nil {
+		return err
+	}
+
+	pattern := strings.ToUpper(c.QueryParam("key"))
+	fingerprint := strings.ToLower(c.QueryParam("fingerprint"))
+	if pattern == "" && fingerprint == "" {
+		return SendErrorResponse(c, http.StatusBadRequest, fmt.Errorf("key or fingerprint is required"))
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return SendErrorResponse(c, http.StatusBadRequest, fmt.Errorf("key must be a valid pattern such as *STRIPE*"))
+	}
+	if fingerprint != "" && !utils.IsFingerprint(fingerprint) {
+		return SendErrorResponse(c, http.StatusBadRequest, fmt.Errorf("fingerprint must be a hex encoded SHA-256 hash"))
+	}
+
+	dbCtx, cancel := GetDBContext()
+	defer cancel()
+
+	found, err := findVariables(dbCtx, ctx.Queries, claims.UserID, pattern, fingerprint)
+	if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch variables"))
+	}
+
+	resp := []VariableSearchResult{}
+	for _, v := range found {
+		resp = append(resp, v.VariableSearchResult)
+	}
+
+	if fingerprint != "" {
+		matched := make(map[string][]string)
+		var projectIDs []string
+		for _, r := range resp {
+			projectID := string(r.Project.ID)
+			if _, ok := matched[projectID]; !ok {
+				projectIDs = append(projectIDs, projectID)
+			}
+			matched[projectID] = append(matched[projectID], r.Key)
+		}
+		for _, projectID := range projectIDs {
+			details := fmt.Sprintf("value fingerprint %s matched %s", fingerprint[:12], strings.Join(matched[projectID], ", "))
+			if err := recordAuditLog(dbCtx, ctx, projectID, claims.UserID, AuditVariablesSearched, details); err != nil {
+				return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to record audit log"))
+			}
+		}
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
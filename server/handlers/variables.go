@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"fmt"
 	"net/http"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
@@ -12,39 +14,232 @@ import (
 	shared "ytsruh.com/envoy/shared"
 )
 
+// CreateEnvironmentVariableRequest creates a variable from a value or, when
+// Generate holds a generator spec such as "base64:32", from a value generated
+// on the server. Generated variables default to the secret type. RotateAfter
+// and ExpiresAt optionally track when the value must be rotated. File variables
+// take base64 encoded contents and may set the filename and mode they are
+// written with. Labels are free-form name=value tags used to filter lists.
 type CreateEnvironmentVariableRequest struct {
-	Key         string `json:"key" validate:"required"`
+	Key           string            `json:"key" validate:"required"`
+	Value         string            `json:"value" validate:"required_without=Generate,typed_value"`
+	Generate      string            `json:"generate" validate:"omitempty,generator"`
+	Description   string            `json:"description" validate:"max=500"`
+	Type          string            `json:"type" validate:"variable_type"`
+	AllowedValues []string          `json:"allowed_values" validate:"omitempty,dive,required,excludes=0x2C"`
+	RotateAfter   string            `json:"rotate_after" validate:"omitempty,duration"`
+	ExpiresAt     shared.Timestamp  `json:"expires_at"`
+	Filename      string            `json:"filename" validate:"omitempty,filename"`
+	Mode          string            `json:"mode" validate:"omitempty,file_mode"`
+	Labels        map[string]string `json:"labels" validate:"omitempty,labels"`
+}
+
+// UpdateEnvironmentVariableRequest replaces a variable. An empty type keeps the
+// variable's current type and allowed values, and an empty filename or mode
+// keeps those of a file variable. Omitting labels keeps the current labels.
+type UpdateEnvironmentVariableRequest struct {
+	Key           string            `json:"key" validate:"required"`
+	Value         string            `json:"value" validate:"required,typed_value"`
+	Description   string            `json:"description" validate:"max=500"`
+	Type          string            `json:"type" validate:"variable_type"`
+	AllowedValues []string          `json:"allowed_values" validate:"omitempty,dive,required,excludes=0x2C"`
+	Filename      string            `json:"filename" validate:"omitempty,filename"`
+	Mode          string            `json:"mode" validate:"omitempty,file_mode"`
+	Labels        map[string]string `json:"labels" validate:"omitempty,labels"`
+}
+
+type UpsertEnvironmentVariableRequest struct {
 	Value       string `json:"value" validate:"required"`
 	Description string `json:"description" validate:"max=500"`
 }
 
-type UpdateEnvironmentVariableRequest struct {
+const (
+	BatchOperationSet    = "set"
+	BatchOperationDelete = "delete"
+)
+
+// maxBatchOperations caps how many operations a single batch request may contain.
+const maxBatchOperations = 1000
+
+type BatchVariableOperation struct {
+	Op          string `json:"op" validate:"required,oneof=set delete"`
 	Key         string `json:"key" validate:"required"`
-	Value       string `json:"value" validate:"required"`
+	Value       string `json:"value"`
 	Description string `json:"description" validate:"max=500"`
 }
 
+type BatchEnvironmentVariablesRequest struct {
+	Operations []BatchVariableOperation `json:"operations" validate:"dive"`
+}
+
+type ReplaceEnvironmentVariablesRequest struct {
+	Variables map[string]string `json:"variables"`
+}
+
+type ReplaceEnvironmentVariablesResponse struct {
+	Added     []string `json:"added"`
+	Changed   []string `json:"changed"`
+	Removed   []string `json:"removed"`
+	Unchanged int      `json:"unchanged"`
+	DryRun    bool     `json:"dry_run"`
+}
+
+type BatchVariableResult struct {
+	Key    string `json:"key"`
+	Op     string `json:"op"`
+	Status string `json:"status"`
+}
+
+type BatchEnvironmentVariablesResponse struct {
+	Results []BatchVariableResult `json:"results"`
+	Created int                   `json:"created"`
+	Updated int                   `json:"updated"`
+	Deleted int                   `json:"deleted"`
+}
+
+const (
+	AuditVariableRevealed  = "variable.revealed"
+	AuditVariablesRevealed = "variables.revealed"
+)
+
 type EnvironmentVariableResponse struct {
-	ID            shared.EnvironmentVariableID `json:"id"`
-	EnvironmentID shared.EnvironmentID         `json:"environment_id"`
-	Key           string                       `json:"key"`
-	Value         string                       `json:"value"`
-	Description   *string                      `json:"description"`
-	CreatedAt     shared.Timestamp             `json:"created_at"`
-	UpdatedAt     shared.Timestamp             `json:"updated_at"`
+	ID              shared.EnvironmentVariableID `json:"id"`
+	EnvironmentID   shared.EnvironmentID         `json:"environment_id"`
+	Key             string                       `json:"key"`
+	Value           string                       `json:"value"`
+	Description     *string                      `json:"description"`
+	Redacted        bool                         `json:"redacted,omitempty"`
+	Masked          bool                         `json:"masked,omitempty"`
+	Type            string                       `json:"type"`
+	AllowedValues   []string                     `json:"allowed_values,omitempty"`
+	Filename        string                       `json:"filename,omitempty"`
+	Mode            string                       `json:"mode,omitempty"`
+	Labels          map[string]string            `json:"labels,omitempty"`
+	Origin          string                       `json:"origin,omitempty"`
+	InheritedFrom   *EnvironmentRef              `json:"inherited_from,omitempty"`
+	ResolutionError string                       `json:"resolution_error,omitempty"`
+	RotateAfter     string                       `json:"rotate_after,omitempty"`
+	RotateBy        shared.Timestamp             `json:"rotate_by"`
+	RotationOverdue bool                         `json:"rotation_overdue,omitempty"`
+	ExpiresAt       shared.Timestamp             `json:"expires_at"`
+	Expired         bool                         `json:"expired,omitempty"`
+	CreatedAt       shared.Timestamp             `json:"created_at"`
+	UpdatedAt       shared.Timestamp             `json:"updated_at"`
 }
 
-func CreateEnvironmentVariable(c echo.Context, ctx *HandlerContext) error {
-	projectID := c.Param("project_id")
-	environmentID := c.Param("environment_id")
+func newEnvironmentVariableResponse(v database.EnvironmentVariable) EnvironmentVariableResponse {
+	resp := EnvironmentVariableResponse{
+		ID:            shared.EnvironmentVariableID(v.ID),
+		EnvironmentID: shared.EnvironmentID(v.EnvironmentID),
+		Key:           v.Key,
+		Value:         v.Value,
+		Description:   shared.NullStringToStringPtr(v.Description),
+		Type:          variableType(v),
+		AllowedValues: utils.SplitAllowedValues(v.AllowedValues.String),
+		Filename:      v.Filename.String,
+		Mode:          v.FileMode.String,
+		Labels:        utils.ParseLabels(v.Labels.String),
+		CreatedAt:     shared.FromTime(v.CreatedAt.Time),
+		UpdatedAt:     shared.FromTime(v.UpdatedAt.Time),
+	}
+	setRotationStatus(&resp, v, time.Now())
+	return resp
+}
 
-	claims, err := GetUserOrUnauthorized(c)
+// variableType returns the type of v, treating untyped variables as strings.
+func variableType(v database.EnvironmentVariable) string {
+	if v.Type == "" {
+		return utils.VariableTypeString
+	}
+	return v.Type
+}
+
+// validateExistingValue checks that value is valid for the type of an existing
+// variable, for writes that set a value without restating the type.
+func validateExistingValue(v database.EnvironmentVariable, value string) error {
+	if err := utils.ValidateVariableValue(v.Type, utils.SplitAllowedValues(v.AllowedValues.String), value); err != nil {
+		return fmt.Errorf("invalid value for %s (%s): %v", v.Key, variableType(v), err)
+	}
+	return nil
+}
+
+// typeColumns converts a requested type and allowed values into the stored
+// columns. Allowed values are only accepted for enums.
+func typeColumns(variableType string, allowedValues []string) (string, sql.NullString, error) {
+	if variableType == "" {
+		variableType = utils.VariableTypeString
+	}
+	if variableType != utils.VariableTypeEnum {
+		if len(allowedValues) > 0 {
+			return "", sql.NullString{}, fmt.Errorf("allowed_values only apply to enum variables")
+		}
+		return variableType, sql.NullString{}, nil
+	}
+	joined := strings.Join(allowedValues, ",")
+	return variableType, sql.NullString{String: joined, Valid: joined != ""}, nil
+}
+
+// fileColumns returns the stored filename and mode of a variable, which only
+// apply to file variables. Unset values fall back to those of current, then to
+// the key and DefaultFileMode.
+func fileColumns(variableType, key, filename, mode string, current database.EnvironmentVariable) (sql.NullString, sql.NullString, error) {
+	if variableType != utils.VariableTypeFile {
+		if filename != "" || mode != "" {
+			return sql.NullString{}, sql.NullString{}, fmt.Errorf("filename and mode only apply to file variables")
+		}
+		return sql.NullString{}, sql.NullString{}, nil
+	}
+	if filename == "" {
+		filename = current.Filename.String
+	}
+	if filename == "" {
+		filename = key
+	}
+	if !utils.IsFilename(filename) {
+		return sql.NullString{}, sql.NullString{}, fmt.Errorf("%s is not a valid filename, set one explicitly", filename)
+	}
+	if mode == "" {
+		mode = current.FileMode.String
+	}
+	if mode == "" {
+		mode = utils.DefaultFileMode
+	}
+	return sql.NullString{String: filename, Valid: true}, sql.NullString{String: mode, Valid: true}, nil
+}
+
+// redactValue hides the value from members with metadata-only access.
+func redactValue(resp *EnvironmentVariableResponse, role string) {
+	if role == utils.RoleMetadata {
+		resp.Value = ""
+		resp.Redacted = true
+	}
+}
+
+// expandValue resolves references in the response value. If they cannot be
+// resolved the template is returned unchanged along with the reason.
+func expandValue(in *interpolator, env database.Environment, resp *EnvironmentVariableResponse) {
+	value, err := in.Expand(env, resp.Value)
 	if err != nil {
-		return err
+		resp.ResolutionError = err.Error()
+		return
 	}
+	resp.Value = value
+}
 
-	if err := ctx.AccessControl.RequireEditor(c.Request().Context(), projectID, claims.UserID); err != nil {
-		return SendErrorResponse(c, http.StatusForbidden, err)
+// maskValue hides the value unless it was explicitly revealed. Metadata-only
+// members never see values, revealed or not.
+func maskValue(resp *EnvironmentVariableResponse, role string, reveal bool) {
+	redactValue(resp, role)
+	if !resp.Redacted && !reveal {
+		resp.Value = ""
+		resp.Masked = true
+	}
+}
+
+func CreateEnvironmentVariable(c echo.Context, ctx *HandlerContext) error {
+	resources, err := GetResources(c)
+	if err != nil {
+		return err
 	}
 
 	var req CreateEnvironmentVariableRequest
@@ -55,6 +250,41 @@ func CreateEnvironmentVariable(c echo.Context, ctx *HandlerContext) error {
 	dbCtx, cancel := GetDBContext()
 	defer cancel()
 
+	_, err = ctx.Queries.GetEnvironmentVariableByKey(dbCtx, database.GetEnvironmentVariableByKeyParams{
+		EnvironmentID: resources.Environment.ID,
+		Key:           req.Key,
+	})
+	if err == nil {
+		return SendErrorResponse(c, http.StatusConflict, fmt.Errorf("a variable with this key already exists in this environment"))
+	}
+
+	if req.Generate != "" {
+		if req.Value != "" {
+			return SendErrorResponse(c, http.StatusBadRequest, fmt.Errorf("value and generate cannot both be set"))
+		}
+		req.Value, err = utils.GenerateValue(req.Generate)
+		if err != nil {
+			return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to generate value"))
+		}
+		if req.Type == "" {
+			req.Type = utils.VariableTypeSecret
+		}
+		if err := utils.ValidateVariableValue(req.Type, req.AllowedValues, req.Value); err != nil {
+			return SendErrorResponse(c, http.StatusBadRequest, fmt.Errorf("generated value does not match type %s", req.Type))
+		}
+	}
+
+	variableType, allowedValues, err := typeColumns(req.Type, req.AllowedValues)
+	if err != nil {
+		return SendErrorResponse(c, http.StatusBadRequest, err)
+	}
+	filename, mode, err := fileColumns(variableType, req.Key, req.Filename, req.Mode, database.EnvironmentVariable{})
+	if err != nil {
+		return SendErrorResponse(c, http.StatusBadRequest, err)
+	}
+
+	rotateAfter, expiresAt := rotationColumns(req.RotateAfter, req.ExpiresAt)
+
 	now := time.Now()
 	variableID := utils.GenerateUUID()
 	variable, err := ctx.Queries.CreateEnvironmentVariable(dbCtx, database.CreateEnvironmentVariableParams{
@@ -62,30 +292,46 @@ func CreateEnvironmentVariable(c echo.Context, ctx *HandlerContext) error {
 		Key:           req.Key,
 		Value:         req.Value,
 		Description:   sql.NullString{String: req.Description, Valid: req.Description != ""},
-		EnvironmentID: environmentID,
+		EnvironmentID: resources.Environment.ID,
 		CreatedAt:     sql.NullTime{Time: now, Valid: true},
 		UpdatedAt:     sql.NullTime{Time: now, Valid: true},
+		Type:          variableType,
+		AllowedValues: allowedValues,
+		RotateAfter:   rotateAfter,
+		ExpiresAt:     expiresAt,
+		RotatedAt:     sql.NullTime{Time: now, Valid: true},
+		Filename:      filename,
+		FileMode:      mode,
+		Labels:        labelsColumn(req.Labels),
 	})
 	if err != nil {
 		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to create environment variable"))
 	}
 
-	resp := EnvironmentVariableResponse{
-		ID:            shared.EnvironmentVariableID(variable.ID),
-		EnvironmentID: shared.EnvironmentID(variable.EnvironmentID),
-		Key:           variable.Key,
-		Value:         variable.Value,
-		Description:   shared.NullStringToStringPtr(variable.Description),
-		CreatedAt:     shared.FromTime(variable.CreatedAt.Time),
-		UpdatedAt:     shared.FromTime(variable.UpdatedAt.Time),
-	}
+	resp := newEnvironmentVariableResponse(variable)
 
 	return c.JSON(http.StatusCreated, resp)
 }
 
 func GetEnvironmentVariable(c echo.Context, ctx *HandlerContext) error {
-	id := c.Param("id")
+	resources, err := GetResources(c)
+	if err != nil {
+		return err
+	}
 
+	resp := newEnvironmentVariableResponse(*resources.Variable)
+	maskValue(&resp, resources.Role, false)
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// RevealEnvironmentVariable returns a single variable with its plaintext value
+// and records the reveal in the project's audit log.
+func RevealEnvironmentVariable(c echo.Context, ctx *HandlerContext) error {
+	resources, err := GetResources(c)
+	if err != nil {
+		return err
+	}
 	claims, err := GetUserOrUnauthorized(c)
 	if err != nil {
 		return err
@@ -94,80 +340,99 @@ func GetEnvironmentVariable(c echo.Context, ctx *HandlerContext) error {
 	dbCtx, cancel := GetDBContext()
 	defer cancel()
 
-	variable, err := ctx.Queries.GetAccessibleEnvironmentVariable(dbCtx, database.GetAccessibleEnvironmentVariableParams{
-		ID:      id,
-		OwnerID: claims.UserID,
-		UserID:  claims.UserID,
-	})
-	if err == sql.ErrNoRows {
-		return SendErrorResponse(c, http.StatusNotFound, fmt.Errorf("environment variable not found or access denied"))
-	} else if err != nil {
-		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch environment variable"))
+	variable := resources.Variable
+	details := fmt.Sprintf("%s/%s", resources.Environment.Name, variable.Key)
+	if err := recordAuditLog(dbCtx, ctx, resources.Project.ID, claims.UserID, AuditVariableRevealed, details); err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to record audit log"))
 	}
 
-	resp := EnvironmentVariableResponse{
-		ID:            shared.EnvironmentVariableID(variable.ID),
-		EnvironmentID: shared.EnvironmentID(variable.EnvironmentID),
-		Key:           variable.Key,
-		Value:         variable.Value,
-		Description:   shared.NullStringToStringPtr(variable.Description),
-		CreatedAt:     shared.FromTime(variable.CreatedAt.Time),
-		UpdatedAt:     shared.FromTime(variable.UpdatedAt.Time),
+	resp := newEnvironmentVariableResponse(*variable)
+	if c.QueryParam("raw") != "true" {
+		expandValue(newInterpolator(dbCtx, ctx, claims.UserID), *resources.Environment, &resp)
 	}
 
 	return c.JSON(http.StatusOK, resp)
 }
 
+// ListEnvironmentVariables returns the variables of an environment, including
+// those inherited from its parents and the project unless ?inherit=false is
+// passed. Each variable carries its origin. Values are masked; passing
+// ?reveal=true returns plaintext values and is audited. Repeated ?label=
+// parameters such as label=team=payments or label=deprecated keep only the
+// variables carrying every label.
 func ListEnvironmentVariables(c echo.Context, ctx *HandlerContext) error {
-	projectID := c.Param("project_id")
-	environmentID := c.Param("environment_id")
-
+	resources, err := GetResources(c)
+	if err != nil {
+		return err
+	}
 	claims, err := GetUserOrUnauthorized(c)
 	if err != nil {
 		return err
 	}
 
-	if err := ctx.AccessControl.RequireViewer(c.Request().Context(), projectID, claims.UserID); err != nil {
-		return SendErrorResponse(c, http.StatusForbidden, err)
+	reveal := c.QueryParam("reveal") == "true"
+	if reveal && !utils.RoleAtLeast(resources.Role, utils.RoleViewer) {
+		return SendErrorResponse(c, http.StatusForbidden, fmt.Errorf("insufficient permissions to reveal variable values"))
 	}
 
 	dbCtx, cancel := GetDBContext()
 	defer cancel()
 
-	variables, err := ctx.Queries.ListEnvironmentVariablesByEnvironment(dbCtx, environmentID)
-	if err != nil {
-		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch environment variables"))
+	var variables []resolvedVariable
+	if c.QueryParam("inherit") == "false" {
+		own, err := ctx.Queries.ListEnvironmentVariablesByEnvironment(dbCtx, resources.Environment.ID)
+		if err != nil {
+			return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch environment variables"))
+		}
+		for _, v := range own {
+			variables = append(variables, resolvedVariable{EnvironmentVariable: v})
+		}
+	} else {
+		variables, err = resolveEnvironmentVariables(dbCtx, ctx.Queries, *resources.Environment)
+		if err != nil {
+			return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to fetch environment variables"))
+		}
+	}
+
+	if selectors := c.QueryParams()["label"]; len(selectors) > 0 {
+		variables = filterByLabels(variables, selectors)
+	}
+
+	if reveal {
+		details := fmt.Sprintf("%s: %d variable(s)", resources.Environment.Name, len(variables))
+		if err := recordAuditLog(dbCtx, ctx, resources.Project.ID, claims.UserID, AuditVariablesRevealed, details); err != nil {
+			return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to record audit log"))
+		}
+	}
+
+	var expander *interpolator
+	if reveal && c.QueryParam("raw") != "true" {
+		expander = newInterpolator(dbCtx, ctx, claims.UserID)
 	}
 
 	var resp []EnvironmentVariableResponse
 	for _, v := range variables {
-		resp = append(resp, EnvironmentVariableResponse{
-			ID:            shared.EnvironmentVariableID(v.ID),
-			EnvironmentID: shared.EnvironmentID(v.EnvironmentID),
-			Key:           v.Key,
-			Value:         v.Value,
-			Description:   shared.NullStringToStringPtr(v.Description),
-			CreatedAt:     shared.FromTime(v.CreatedAt.Time),
-			UpdatedAt:     shared.FromTime(v.UpdatedAt.Time),
-		})
+		item := newEnvironmentVariableResponse(v.EnvironmentVariable)
+		item.Origin = v.Origin()
+		if v.InheritedFrom != nil {
+			item.InheritedFrom = &EnvironmentRef{ID: shared.EnvironmentID(v.InheritedFrom.ID), Name: v.InheritedFrom.Name}
+		}
+		if expander != nil {
+			expandValue(expander, *resources.Environment, &item)
+		}
+		maskValue(&item, resources.Role, reveal)
+		resp = append(resp, item)
 	}
 
 	return c.JSON(http.StatusOK, resp)
 }
 
 func UpdateEnvironmentVariable(c echo.Context, ctx *HandlerContext) error {
-	projectID := c.Param("project_id")
-	id := c.Param("id")
-
-	claims, err := GetUserOrUnauthorized(c)
+	resources, err := GetResources(c)
 	if err != nil {
 		return err
 	}
 
-	if err := ctx.AccessControl.RequireEditor(c.Request().Context(), projectID, claims.UserID); err != nil {
-		return SendErrorResponse(c, http.StatusForbidden, err)
-	}
-
 	var req UpdateEnvironmentVariableRequest
 	if err := BindAndValidate(c, &req); err != nil {
 		return err
@@ -176,51 +441,360 @@ func UpdateEnvironmentVariable(c echo.Context, ctx *HandlerContext) error {
 	dbCtx, cancel := GetDBContext()
 	defer cancel()
 
+	if req.Key != resources.Variable.Key {
+		_, err := ctx.Queries.GetEnvironmentVariableByKey(dbCtx, database.GetEnvironmentVariableByKeyParams{
+			EnvironmentID: resources.Variable.EnvironmentID,
+			Key:           req.Key,
+		})
+		if err == nil {
+			return SendErrorResponse(c, http.StatusConflict, fmt.Errorf("a variable with this key already exists in this environment"))
+		}
+	}
+
+	variableType, allowedValues := resources.Variable.Type, resources.Variable.AllowedValues
+	if req.Type == "" {
+		if err := validateExistingValue(*resources.Variable, req.Value); err != nil {
+			return SendErrorResponse(c, http.StatusBadRequest, err)
+		}
+	} else {
+		variableType, allowedValues, err = typeColumns(req.Type, req.AllowedValues)
+		if err != nil {
+			return SendErrorResponse(c, http.StatusBadRequest, err)
+		}
+	}
+	filename, mode, err := fileColumns(variableType, req.Key, req.Filename, req.Mode, *resources.Variable)
+	if err != nil {
+		return SendErrorResponse(c, http.StatusBadRequest, err)
+	}
+
+	labels := resources.Variable.Labels
+	if req.Labels != nil {
+		labels = labelsColumn(req.Labels)
+	}
+
 	now := time.Now()
+	// A new value restarts the rotation interval, and the old value's expiry
+	// no longer applies.
+	rotatedAt, expiresAt := resources.Variable.RotatedAt, resources.Variable.ExpiresAt
+	if req.Value != resources.Variable.Value {
+		rotatedAt, expiresAt = sql.NullTime{Time: now, Valid: true}, sql.NullTime{}
+	}
 	variable, err := ctx.Queries.UpdateEnvironmentVariable(dbCtx, database.UpdateEnvironmentVariableParams{
-		Key:         req.Key,
-		Value:       req.Value,
-		Description: sql.NullString{String: req.Description, Valid: req.Description != ""},
-		UpdatedAt:   sql.NullTime{Time: now, Valid: true},
-		ID:          id,
+		Key:           req.Key,
+		Value:         req.Value,
+		Description:   sql.NullString{String: req.Description, Valid: req.Description != ""},
+		UpdatedAt:     sql.NullTime{Time: now, Valid: true},
+		Type:          variableType,
+		AllowedValues: allowedValues,
+		RotatedAt:     rotatedAt,
+		ExpiresAt:     expiresAt,
+		Filename:      filename,
+		FileMode:      mode,
+		Labels:        labels,
+		ID:            resources.Variable.ID,
 	})
 	if err != nil {
 		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to update environment variable"))
 	}
 
-	resp := EnvironmentVariableResponse{
-		ID:            shared.EnvironmentVariableID(variable.ID),
-		EnvironmentID: shared.EnvironmentID(variable.EnvironmentID),
-		Key:           variable.Key,
-		Value:         variable.Value,
-		Description:   shared.NullStringToStringPtr(variable.Description),
-		CreatedAt:     shared.FromTime(variable.CreatedAt.Time),
-		UpdatedAt:     shared.FromTime(variable.UpdatedAt.Time),
-	}
+	resp := newEnvironmentVariableResponse(variable)
 
 	return c.JSON(http.StatusOK, resp)
 }
 
-func DeleteEnvironmentVariable(c echo.Context, ctx *HandlerContext) error {
-	projectID := c.Param("project_id")
-	id := c.Param("id")
-
-	claims, err := GetUserOrUnauthorized(c)
+// UpsertEnvironmentVariable sets the value of the variable with the given key,
+// creating it if the environment does not have one yet.
+func UpsertEnvironmentVariable(c echo.Context, ctx *HandlerContext) error {
+	resources, err := GetResources(c)
 	if err != nil {
 		return err
 	}
 
-	if err := ctx.AccessControl.RequireEditor(c.Request().Context(), projectID, claims.UserID); err != nil {
-		return SendErrorResponse(c, http.StatusForbidden, err)
+	key := c.Param("key")
+	if key == "" {
+		return SendErrorResponse(c, http.StatusBadRequest, fmt.Errorf("variable key is required"))
+	}
+
+	var req UpsertEnvironmentVariableRequest
+	if err := BindAndValidate(c, &req); err != nil {
+		return err
 	}
 
 	dbCtx, cancel := GetDBContext()
 	defer cancel()
 
-	err = ctx.Queries.DeleteEnvironmentVariable(dbCtx, id)
+	existing, err := ctx.Queries.GetEnvironmentVariableByKey(dbCtx, database.GetEnvironmentVariableByKeyParams{
+		EnvironmentID: resources.Environment.ID,
+		Key:           key,
+	})
+	if err == nil {
+		if err := validateExistingValue(existing, req.Value); err != nil {
+			return SendErrorResponse(c, http.StatusBadRequest, err)
+		}
+	}
+
+	now := time.Now()
+	variableID := utils.GenerateUUID()
+	variable, err := ctx.Queries.UpsertEnvironmentVariable(dbCtx, database.UpsertEnvironmentVariableParams{
+		ID:            variableID,
+		EnvironmentID: resources.Environment.ID,
+		Key:           key,
+		Value:         req.Value,
+		Description:   sql.NullString{String: req.Description, Valid: req.Description != ""},
+		CreatedAt:     sql.NullTime{Time: now, Valid: true},
+		UpdatedAt:     sql.NullTime{Time: now, Valid: true},
+		Type:          utils.VariableTypeString,
+		RotatedAt:     sql.NullTime{Time: now, Valid: true},
+	})
+	if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to save environment variable"))
+	}
+
+	status := http.StatusOK
+	if variable.ID == variableID {
+		status = http.StatusCreated
+	}
+
+	return c.JSON(status, newEnvironmentVariableResponse(variable))
+}
+
+func DeleteEnvironmentVariable(c echo.Context, ctx *HandlerContext) error {
+	resources, err := GetResources(c)
+	if err != nil {
+		return err
+	}
+
+	dbCtx, cancel := GetDBContext()
+	defer cancel()
+
+	err = ctx.Queries.DeleteEnvironmentVariable(dbCtx, resources.Variable.ID)
 	if err != nil {
 		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("failed to delete environment variable"))
 	}
 
 	return c.JSON(http.StatusOK, map[string]string{"message": "Environment variable deleted successfully"})
 }
+
+// BatchEnvironmentVariables applies a list of set and delete operations to an
+// environment in a single transaction. Either every operation is applied or none are.
+func BatchEnvironmentVariables(c echo.Context, ctx *HandlerContext) error {
+	resources, err := GetResources(c)
+	if err != nil {
+		return err
+	}
+
+	var req BatchEnvironmentVariablesRequest
+	if err := BindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	if len(req.Operations) == 0 {
+		return SendErrorResponse(c, http.StatusBadRequest, fmt.Errorf("at least one operation is required"))
+	}
+	if len(req.Operations) > maxBatchOperations {
+		return SendErrorResponse(c, http.StatusBadRequest, fmt.Errorf("a batch may contain at most %d operations", maxBatchOperations))
+	}
+
+	seen := make(map[string]bool, len(req.Operations))
+	for _, op := range req.Operations {
+		if seen[op.Key] {
+			return SendErrorResponse(c, http.StatusBadRequest, fmt.Errorf("key %s appears more than once in the batch", op.Key))
+		}
+		seen[op.Key] = true
+		if op.Op == BatchOperationSet && op.Value == "" {
+			return SendErrorResponse(c, http.StatusBadRequest, fmt.Errorf("value is required to set %s", op.Key))
+		}
+	}
+
+	dbCtx, cancel := GetDBContext()
+	defer cancel()
+
+	environmentID := resources.Environment.ID
+	resp := BatchEnvironmentVariablesResponse{Results: make([]BatchVariableResult, 0, len(req.Operations))}
+	var invalid error
+	err = ctx.Tx.ExecTx(dbCtx, func(q database.Querier) error {
+		now := time.Now()
+		for _, op := range req.Operations {
+			result := BatchVariableResult{Key: op.Key, Op: op.Op}
+
+			switch op.Op {
+			case BatchOperationSet:
+				existing, err := q.GetEnvironmentVariableByKey(dbCtx, database.GetEnvironmentVariableByKeyParams{
+					EnvironmentID: environmentID,
+					Key:           op.Key,
+				})
+				if err == nil {
+					if err := validateExistingValue(existing, op.Value); err != nil {
+						invalid = err
+						return err
+					}
+				}
+
+				variableID := utils.GenerateUUID()
+				variable, err := q.UpsertEnvironmentVariable(dbCtx, database.UpsertEnvironmentVariableParams{
+					ID:            variableID,
+					EnvironmentID: environmentID,
+					Key:           op.Key,
+					Value:         op.Value,
+					Description:   sql.NullString{String: op.Description, Valid: op.Description != ""},
+					CreatedAt:     sql.NullTime{Time: now, Valid: true},
+					UpdatedAt:     sql.NullTime{Time: now, Valid: true},
+					Type:          utils.VariableTypeString,
+					RotatedAt:     sql.NullTime{Time: now, Valid: true},
+				})
+				if err != nil {
+					return fmt.Errorf("failed to set %s", op.Key)
+				}
+				if variable.ID == variableID {
+					result.Status = "created"
+					resp.Created++
+				} else {
+					result.Status = "updated"
+					resp.Updated++
+				}
+			case BatchOperationDelete:
+				rows, err := q.DeleteEnvironmentVariableByKey(dbCtx, database.DeleteEnvironmentVariableByKeyParams{
+					EnvironmentID: environmentID,
+					Key:           op.Key,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to delete %s", op.Key)
+				}
+				if rows == 0 {
+					result.Status = "not_found"
+				} else {
+					result.Status = "deleted"
+					resp.Deleted++
+				}
+			}
+
+			resp.Results = append(resp.Results, result)
+		}
+		return nil
+	})
+	if invalid != nil {
+		return SendErrorResponse(c, http.StatusBadRequest, fmt.Errorf("batch rejected and no changes were applied: %v", invalid))
+	}
+	if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("batch failed and no changes were applied: %v", err))
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// ReplaceEnvironmentVariables makes an environment's variables exactly match the
+// submitted set: missing keys are added, differing values are changed and keys
+// not in the request are removed, all in one transaction. File variables are
+// never removed since .env files cannot hold them. With ?dry_run=true the
+// differences are computed and returned without being applied.
+func ReplaceEnvironmentVariables(c echo.Context, ctx *HandlerContext) error {
+	resources, err := GetResources(c)
+	if err != nil {
+		return err
+	}
+
+	var req ReplaceEnvironmentVariablesRequest
+	if err := BindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	for key, value := range req.Variables {
+		if key == "" {
+			return SendErrorResponse(c, http.StatusBadRequest, fmt.Errorf("variable keys must not be empty"))
+		}
+		if value == "" {
+			return SendErrorResponse(c, http.StatusBadRequest, fmt.Errorf("value is required to set %s", key))
+		}
+	}
+
+	dryRun := c.QueryParam("dry_run") == "true"
+
+	dbCtx, cancel := GetDBContext()
+	defer cancel()
+
+	environmentID := resources.Environment.ID
+	resp := ReplaceEnvironmentVariablesResponse{
+		Added:   []string{},
+		Changed: []string{},
+		Removed: []string{},
+		DryRun:  dryRun,
+	}
+	var invalid error
+	err = ctx.Tx.ExecTx(dbCtx, func(q database.Querier) error {
+		existing, err := q.ListEnvironmentVariablesByEnvironment(dbCtx, environmentID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch environment variables")
+		}
+
+		current := make(map[string]string, len(existing))
+		for _, v := range existing {
+			current[v.Key] = v.Value
+			value, ok := req.Variables[v.Key]
+			if !ok {
+				// File variables cannot be expressed in a .env file, so a
+				// replace leaves them alone rather than removing them.
+				if v.Type != utils.VariableTypeFile {
+					resp.Removed = append(resp.Removed, v.Key)
+				}
+				continue
+			}
+			if err := validateExistingValue(v, value); err != nil {
+				invalid = err
+				return err
+			}
+		}
+		for key, value := range req.Variables {
+			currentValue, ok := current[key]
+			switch {
+			case !ok:
+				resp.Added = append(resp.Added, key)
+			case currentValue != value:
+				resp.Changed = append(resp.Changed, key)
+			default:
+				resp.Unchanged++
+			}
+		}
+		sort.Strings(resp.Added)
+		sort.Strings(resp.Changed)
+		sort.Strings(resp.Removed)
+
+		if dryRun {
+			return nil
+		}
+
+		now := time.Now()
+		for _, key := range append(append([]string{}, resp.Added...), resp.Changed...) {
+			_, err := q.UpsertEnvironmentVariable(dbCtx, database.UpsertEnvironmentVariableParams{
+				ID:            utils.GenerateUUID(),
+				EnvironmentID: environmentID,
+				Key:           key,
+				Value:         req.Variables[key],
+				CreatedAt:     sql.NullTime{Time: now, Valid: true},
+				UpdatedAt:     sql.NullTime{Time: now, Valid: true},
+				Type:          utils.VariableTypeString,
+				RotatedAt:     sql.NullTime{Time: now, Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to set %s", key)
+			}
+		}
+		for _, key := range resp.Removed {
+			_, err := q.DeleteEnvironmentVariableByKey(dbCtx, database.DeleteEnvironmentVariableByKeyParams{
+				EnvironmentID: environmentID,
+				Key:           key,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to delete %s", key)
+			}
+		}
+		return nil
+	})
+	if invalid != nil {
+		return SendErrorResponse(c, http.StatusBadRequest, fmt.Errorf("replace rejected and no changes were applied: %v", invalid))
+	}
+	if err != nil {
+		return SendErrorResponse(c, http.StatusInternalServerError, fmt.Errorf("replace failed and no changes were applied: %v", err))
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
@@ -5,17 +5,34 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"ytsruh.com/envoy/server/utils"
+	shared "ytsruh.com/envoy/shared"
 )
 
 type ProjectRole string
 
 const (
-	RoleOwner  ProjectRole = "owner"
-	RoleEditor ProjectRole = "editor"
-	RoleViewer ProjectRole = "viewer"
+	RoleOwner    ProjectRole = utils.RoleOwner
+	RoleEditor   ProjectRole = utils.RoleEditor
+	RoleViewer   ProjectRole = utils.RoleViewer
+	RoleMetadata ProjectRole = utils.RoleMetadata
 )
 
-func RequireProjectRole(role ProjectRole, accessControl utils.AccessControlService) echo.MiddlewareFunc {
+const ResourcesContextKey = "resources"
+
+// ResourceParams names the route parameters that hold each resource ID.
+// Empty names are not resolved.
+type ResourceParams struct {
+	Project         string
+	Environment     string
+	Variable        string
+	ProjectVariable string
+}
+
+// RequireResourceAccess resolves the project -> environment -> variable chain
+// named by params, rejecting the request unless every segment belongs to its
+// parent and the user holds at least role on the project. The resolved
+// resources are stored in the context for handlers to use.
+func RequireResourceAccess(role ProjectRole, params ResourceParams, accessControl utils.AccessControlService) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			claims, ok := GetUserFromContext(c)
@@ -23,35 +40,36 @@ func RequireProjectRole(role ProjectRole, accessControl utils.AccessControlServi
 				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
 			}
 
-			projectID := c.Param("id")
-
-			var err error
-			switch role {
-			case RoleOwner:
-				err = accessControl.RequireOwner(c.Request().Context(), projectID, claims.UserID)
-			case RoleEditor:
-				err = accessControl.RequireEditor(c.Request().Context(), projectID, claims.UserID)
-			case RoleViewer:
-				err = accessControl.RequireViewer(c.Request().Context(), projectID, claims.UserID)
+			scope := utils.ResourceScope{ProjectID: c.Param(params.Project)}
+			if params.Environment != "" {
+				scope.EnvironmentID = c.Param(params.Environment)
+			}
+			if params.Variable != "" {
+				scope.VariableID = c.Param(params.Variable)
+			}
+			if params.ProjectVariable != "" {
+				scope.ProjectVariableID = c.Param(params.ProjectVariable)
 			}
 
-			if err != nil {
+			resources, err := accessControl.ResolveResources(c.Request().Context(), scope, claims.UserID, string(role))
+			switch err {
+			case nil:
+			case shared.ErrNotFound:
+				return c.JSON(http.StatusNotFound, map[string]string{"error": "resource not found"})
+			case shared.ErrAccessDenied:
 				return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+			default:
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to check permissions"})
 			}
 
+			c.Set(ResourcesContextKey, resources)
+
 			return next(c)
 		}
 	}
 }
 
-func RequireProjectOwner(accessControl utils.AccessControlService) echo.MiddlewareFunc {
-	return RequireProjectRole(RoleOwner, accessControl)
-}
-
-func RequireProjectEditor(accessControl utils.AccessControlService) echo.MiddlewareFunc {
-	return RequireProjectRole(RoleEditor, accessControl)
-}
-
-func RequireProjectViewer(accessControl utils.AccessControlService) echo.MiddlewareFunc {
-	return RequireProjectRole(RoleViewer, accessControl)
+func GetResourcesFromContext(c echo.Context) (*utils.ResolvedResources, bool) {
+	resources, ok := c.Get(ResourcesContextKey).(*utils.ResolvedResources)
+	return resources, ok
 }
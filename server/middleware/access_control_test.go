@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"ytsruh.com/envoy/server/utils"
+	shared "ytsruh.com/envoy/shared"
+)
+
+// stubAccessControl returns a fixed result from ResolveResources and records
+// the scope and role it was asked to resolve.
+type stubAccessControl struct {
+	utils.AccessControlService
+	resources *utils.ResolvedResources
+	err       error
+	scope     utils.ResourceScope
+	minRole   string
+}
+
+func (s *stubAccessControl) ResolveResources(ctx context.Context, scope utils.ResourceScope, userID string, minRole string) (*utils.ResolvedResources, error) {
+	s.scope, s.minRole = scope, minRole
+	return s.resources, s.err
+}
+
+func TestRequireResourceAccess(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"resolved", nil, http.StatusOK},
+		{"not found", shared.ErrNotFound, http.StatusNotFound},
+		{"access denied", shared.ErrAccessDenied, http.StatusForbidden},
+		{"lookup failed", errors.New("database unavailable"), http.StatusInternalServerError},
+	}
+
+	e := echo.New()
+	params := ResourceParams{Project: "project_id", Environment: "environment_id", Variable: "id"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accessControl := &stubAccessControl{resources: &utils.ResolvedResources{Role: utils.RoleEditor}, err: tt.err}
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+			c.SetParamNames("project_id", "environment_id", "id")
+			c.SetParamValues("project", "environment", "variable")
+			c.Set(UserContextKey, &utils.JWTClaims{UserID: "user"})
+
+			called := false
+			handler := RequireResourceAccess(RoleEditor, params, accessControl)(func(c echo.Context) error {
+				called = true
+				if _, ok := GetResourcesFromContext(c); !ok {
+					t.Error("resources were not stored in the context")
+				}
+				return c.NoContent(http.StatusOK)
+			})
+			if err := handler(c); err != nil {
+				t.Fatalf("handler returned error: %v", err)
+			}
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if called != (tt.err == nil) {
+				t.Errorf("next handler called = %v, want %v", called, tt.err == nil)
+			}
+			wantScope := utils.ResourceScope{ProjectID: "project", EnvironmentID: "environment", VariableID: "variable"}
+			if accessControl.scope != wantScope || accessControl.minRole != utils.RoleEditor {
+				t.Errorf("resolved %+v as %q, want %+v as %q", accessControl.scope, accessControl.minRole, wantScope, utils.RoleEditor)
+			}
+		})
+	}
+}
+
+func TestRequireResourceAccessUnauthenticated(t *testing.T) {
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+	handler := RequireResourceAccess(RoleViewer, ResourceParams{Project: "id"}, &stubAccessControl{})(func(c echo.Context) error {
+		t.Error("next handler called without a user")
+		return nil
+	})
+	if err := handler(c); err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
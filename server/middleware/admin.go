@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequireAdmin rejects requests from users who are not instance admins. It
+// must run after JWTAuthMiddleware, which loads the account into the context.
+func RequireAdmin() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			account, ok := GetAccountFromContext(c)
+			if !ok || !account.IsAdmin {
+				return c.JSON(http.StatusForbidden, map[string]string{"error": "admin access required"})
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -1,17 +1,24 @@
 package middleware
 
 import (
+	"database/sql"
 	"net/http"
 	"strings"
 
 	"github.com/labstack/echo/v4"
+	database "ytsruh.com/envoy/server/database/generated"
 	"ytsruh.com/envoy/server/utils"
 	shared "ytsruh.com/envoy/shared"
 )
 
-const UserContextKey = "user"
+const (
+	UserContextKey    = "user"
+	AccountContextKey = "account"
+)
 
-func JWTAuthMiddleware(jwtSecret string) echo.MiddlewareFunc {
+// JWTAuthMiddleware validates the bearer token and loads the user's account,
+// rejecting tokens that belong to deleted or disabled accounts.
+func JWTAuthMiddleware(jwtSecret string, queries database.Querier) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			authHeader := c.Request().Header.Get("Authorization")
@@ -42,7 +49,24 @@ func JWTAuthMiddleware(jwtSecret string) echo.MiddlewareFunc {
 				})
 			}
 
+			account, err := queries.GetUser(c.Request().Context(), claims.UserID)
+			if err == sql.ErrNoRows || (err == nil && account.DeletedAt.Valid) {
+				return c.JSON(http.StatusUnauthorized, map[string]string{
+					"error": "Invalid token",
+				})
+			} else if err != nil {
+				return c.JSON(http.StatusInternalServerError, map[string]string{
+					"error": "Failed to load account",
+				})
+			}
+			if account.DisabledAt.Valid {
+				return c.JSON(http.StatusForbidden, map[string]string{
+					"error": "Account is disabled",
+				})
+			}
+
 			c.Set(UserContextKey, claims)
+			c.Set(AccountContextKey, &account)
 
 			return next(c)
 		}
@@ -58,3 +82,8 @@ func GetUserFromContext(c echo.Context) (*utils.JWTClaims, bool) {
 	claims, ok := user.(*utils.JWTClaims)
 	return claims, ok
 }
+
+func GetAccountFromContext(c echo.Context) (*database.User, bool) {
+	account, ok := c.Get(AccountContextKey).(*database.User)
+	return account, ok
+}
@@ -14,28 +14,32 @@ func (s *Server) RegisterRoutes() {
 	s.RegisterAuthHandlers()
 	s.RegisterProjectHandlers()
 	s.RegisterProjectSharingHandlers()
+	s.RegisterAccessRequestHandlers()
 	s.RegisterEnvironmentHandlers()
 	s.RegisterEnvironmentVariableHandlers()
+	s.RegisterProjectVariableHandlers()
+	s.RegisterProjectSchemaHandlers()
+	s.RegisterAdminHandlers()
 	s.RegisterDocsHandlers()
 	s.RegisterFaviconHandler()
 }
 
 func (s *Server) RegisterHealthHandler() {
-	ctx := handlers.NewHandlerContext(s.dbService.GetQueries(), s.jwtSecret, s.accessControl)
+	ctx := handlers.NewHandlerContext(s.dbService.GetQueries(), s.jwtSecret, s.accessControl, s.dbService)
 	s.router.GET("/health", func(c echo.Context) error {
 		return handlers.Health(c, ctx)
 	})
 }
 
 func (s *Server) RegisterHomeHandler() {
-	ctx := handlers.NewHandlerContext(s.dbService.GetQueries(), s.jwtSecret, s.accessControl)
+	ctx := handlers.NewHandlerContext(s.dbService.GetQueries(), s.jwtSecret, s.accessControl, s.dbService)
 	s.router.GET("/", func(c echo.Context) error {
 		return handlers.Home(c, ctx)
 	})
 }
 
 func (s *Server) RegisterDocsHandlers() {
-	ctx := handlers.NewHandlerContext(s.dbService.GetQueries(), s.jwtSecret, s.accessControl)
+	ctx := handlers.NewHandlerContext(s.dbService.GetQueries(), s.jwtSecret, s.accessControl, s.dbService)
 	s.router.GET("/openapi.json", func(c echo.Context) error {
 		return handlers.OpenAPI(c, ctx)
 	})
@@ -51,8 +55,8 @@ func (s *Server) RegisterFaviconHandler() {
 }
 
 func (s *Server) RegisterAuthHandlers() {
-	auth := middleware.JWTAuthMiddleware(s.jwtSecret)
-	ctx := handlers.NewHandlerContext(s.dbService.GetQueries(), s.jwtSecret, s.accessControl)
+	auth := middleware.JWTAuthMiddleware(s.jwtSecret, s.dbService.GetQueries())
+	ctx := handlers.NewHandlerContext(s.dbService.GetQueries(), s.jwtSecret, s.accessControl, s.dbService)
 	s.router.POST("/auth/register", func(c echo.Context) error {
 		return handlers.Register(c, ctx)
 	})
@@ -68,81 +72,230 @@ func (s *Server) RegisterAuthHandlers() {
 }
 
 func (s *Server) RegisterProjectHandlers() {
-	auth := middleware.JWTAuthMiddleware(s.jwtSecret)
-	ctx := handlers.NewHandlerContext(s.dbService.GetQueries(), s.jwtSecret, s.accessControl)
+	auth := middleware.JWTAuthMiddleware(s.jwtSecret, s.dbService.GetQueries())
+	member := middleware.RequireResourceAccess(middleware.RoleMetadata, middleware.ResourceParams{Project: "id"}, s.accessControl)
+	editor := middleware.RequireResourceAccess(middleware.RoleEditor, middleware.ResourceParams{Project: "id"}, s.accessControl)
+	owner := middleware.RequireResourceAccess(middleware.RoleOwner, middleware.ResourceParams{Project: "id"}, s.accessControl)
+	ctx := handlers.NewHandlerContext(s.dbService.GetQueries(), s.jwtSecret, s.accessControl, s.dbService)
 	s.router.POST("/projects", auth(func(c echo.Context) error {
 		return handlers.CreateProject(c, ctx)
 	}))
-	s.router.GET("/projects/:id", auth(func(c echo.Context) error {
+	s.router.GET("/projects/:id", auth(member(func(c echo.Context) error {
 		return handlers.GetProject(c, ctx)
-	}))
+	})))
 	s.router.GET("/projects", auth(func(c echo.Context) error {
 		return handlers.ListProjects(c, ctx)
 	}))
-	s.router.PUT("/projects/:id", auth(func(c echo.Context) error {
+	s.router.PUT("/projects/:id", auth(editor(func(c echo.Context) error {
 		return handlers.UpdateProject(c, ctx)
-	}))
-	s.router.DELETE("/projects/:id", auth(func(c echo.Context) error {
+	})))
+	s.router.DELETE("/projects/:id", auth(owner(func(c echo.Context) error {
 		return handlers.DeleteProject(c, ctx)
-	}))
+	})))
 }
 
 func (s *Server) RegisterProjectSharingHandlers() {
-	auth := middleware.JWTAuthMiddleware(s.jwtSecret)
-	ctx := handlers.NewHandlerContext(s.dbService.GetQueries(), s.jwtSecret, s.accessControl)
-	s.router.POST("/projects/:id/members", auth(func(c echo.Context) error {
+	auth := middleware.JWTAuthMiddleware(s.jwtSecret, s.dbService.GetQueries())
+	member := middleware.RequireResourceAccess(middleware.RoleMetadata, middleware.ResourceParams{Project: "id"}, s.accessControl)
+	viewer := middleware.RequireResourceAccess(middleware.RoleViewer, middleware.ResourceParams{Project: "id"}, s.accessControl)
+	owner := middleware.RequireResourceAccess(middleware.RoleOwner, middleware.ResourceParams{Project: "id"}, s.accessControl)
+	ctx := handlers.NewHandlerContext(s.dbService.GetQueries(), s.jwtSecret, s.accessControl, s.dbService)
+	s.router.POST("/projects/:id/members", auth(owner(func(c echo.Context) error {
 		return handlers.AddUserToProject(c, ctx)
-	}))
-	s.router.DELETE("/projects/:id/members/:user_id", auth(func(c echo.Context) error {
+	})))
+	s.router.DELETE("/projects/:id/members/:user_id", auth(owner(func(c echo.Context) error {
 		return handlers.RemoveUserFromProject(c, ctx)
-	}))
-	s.router.PUT("/projects/:id/members/:user_id", auth(func(c echo.Context) error {
+	})))
+	s.router.PUT("/projects/:id/members/:user_id", auth(owner(func(c echo.Context) error {
 		return handlers.UpdateUserRole(c, ctx)
-	}))
-	s.router.GET("/projects/:id/members", auth(func(c echo.Context) error {
+	})))
+	s.router.GET("/projects/:id/members", auth(member(func(c echo.Context) error {
 		return handlers.GetProjectUsers(c, ctx)
-	}))
+	})))
+	s.router.POST("/projects/:id/elevate", auth(viewer(func(c echo.Context) error {
+		return handlers.ElevateProjectAccess(c, ctx)
+	})))
+	s.router.GET("/projects/:id/audit-logs", auth(owner(func(c echo.Context) error {
+		return handlers.ListAuditLogs(c, ctx)
+	})))
 	s.router.GET("/user/projects", auth(func(c echo.Context) error {
 		return handlers.ListUserProjects(c, ctx)
 	}))
 }
 
+func (s *Server) RegisterAccessRequestHandlers() {
+	auth := middleware.JWTAuthMiddleware(s.jwtSecret, s.dbService.GetQueries())
+	owner := middleware.RequireResourceAccess(middleware.RoleOwner, middleware.ResourceParams{Project: "id"}, s.accessControl)
+	ctx := handlers.NewHandlerContext(s.dbService.GetQueries(), s.jwtSecret, s.accessControl, s.dbService)
+	s.router.GET("/projects/discover", auth(func(c echo.Context) error {
+		return handlers.DiscoverProjects(c, ctx)
+	}))
+	s.router.POST("/access-requests", auth(func(c echo.Context) error {
+		return handlers.CreateAccessRequest(c, ctx)
+	}))
+	s.router.GET("/projects/:id/access-requests", auth(owner(func(c echo.Context) error {
+		return handlers.ListAccessRequests(c, ctx)
+	})))
+	s.router.POST("/projects/:id/access-requests/:request_id/approve", auth(owner(func(c echo.Context) error {
+		return handlers.ApproveAccessRequest(c, ctx)
+	})))
+	s.router.POST("/projects/:id/access-requests/:request_id/deny", auth(owner(func(c echo.Context) error {
+		return handlers.DenyAccessRequest(c, ctx)
+	})))
+}
+
+func (s *Server) RegisterAdminHandlers() {
+	auth := middleware.JWTAuthMiddleware(s.jwtSecret, s.dbService.GetQueries())
+	admin := middleware.RequireAdmin()
+	ctx := handlers.NewHandlerContext(s.dbService.GetQueries(), s.jwtSecret, s.accessControl, s.dbService)
+	s.router.GET("/admin/users", auth(admin(func(c echo.Context) error {
+		return handlers.AdminListUsers(c, ctx)
+	})))
+	s.router.POST("/admin/users/:user_id/disable", auth(admin(func(c echo.Context) error {
+		return handlers.AdminDisableUser(c, ctx)
+	})))
+	s.router.POST("/admin/users/:user_id/enable", auth(admin(func(c echo.Context) error {
+		return handlers.AdminEnableUser(c, ctx)
+	})))
+	s.router.GET("/admin/projects", auth(admin(func(c echo.Context) error {
+		return handlers.AdminListProjects(c, ctx)
+	})))
+	s.router.POST("/admin/projects/:id/transfer", auth(admin(func(c echo.Context) error {
+		return handlers.AdminTransferProjectOwnership(c, ctx)
+	})))
+	s.router.GET("/admin/stats", auth(admin(func(c echo.Context) error {
+		return handlers.AdminGetStats(c, ctx)
+	})))
+}
+
 func (s *Server) RegisterEnvironmentHandlers() {
-	auth := middleware.JWTAuthMiddleware(s.jwtSecret)
-	ctx := handlers.NewHandlerContext(s.dbService.GetQueries(), s.jwtSecret, s.accessControl)
-	s.router.POST("/projects/:project_id/environments", auth(func(c echo.Context) error {
+	auth := middleware.JWTAuthMiddleware(s.jwtSecret, s.dbService.GetQueries())
+	projectMember := middleware.RequireResourceAccess(middleware.RoleMetadata, middleware.ResourceParams{Project: "project_id"}, s.accessControl)
+	projectViewer := middleware.RequireResourceAccess(middleware.RoleViewer, middleware.ResourceParams{Project: "project_id"}, s.accessControl)
+	projectEditor := middleware.RequireResourceAccess(middleware.RoleEditor, middleware.ResourceParams{Project: "project_id"}, s.accessControl)
+	member := middleware.RequireResourceAccess(middleware.RoleMetadata, middleware.ResourceParams{Project: "project_id", Environment: "id"}, s.accessControl)
+	viewer := middleware.RequireResourceAccess(middleware.RoleViewer, middleware.ResourceParams{Project: "project_id", Environment: "id"}, s.accessControl)
+	editor := middleware.RequireResourceAccess(middleware.RoleEditor, middleware.ResourceParams{Project: "project_id", Environment: "id"}, s.accessControl)
+	ctx := handlers.NewHandlerContext(s.dbService.GetQueries(), s.jwtSecret, s.accessControl, s.dbService)
+	s.router.POST("/projects/:project_id/environments", auth(projectEditor(func(c echo.Context) error {
 		return handlers.CreateEnvironment(c, ctx)
-	}))
-	s.router.GET("/projects/:project_id/environments/:id", auth(func(c echo.Context) error {
+	})))
+	s.router.GET("/projects/:project_id/environments/compare", auth(projectViewer(func(c echo.Context) error {
+		return handlers.CompareEnvironments(c, ctx)
+	})))
+	s.router.POST("/projects/:project_id/environments/promote", auth(projectEditor(func(c echo.Context) error {
+		return handlers.PromoteEnvironment(c, ctx)
+	})))
+	s.router.GET("/projects/:project_id/environments/:id", auth(member(func(c echo.Context) error {
 		return handlers.GetEnvironment(c, ctx)
-	}))
-	s.router.GET("/projects/:project_id/environments", auth(func(c echo.Context) error {
+	})))
+	s.router.GET("/projects/:project_id/environments", auth(projectMember(func(c echo.Context) error {
 		return handlers.ListEnvironments(c, ctx)
-	}))
-	s.router.PUT("/projects/:project_id/environments/:id", auth(func(c echo.Context) error {
+	})))
+	s.router.POST("/projects/:project_id/environments/:id/clone", auth(viewer(func(c echo.Context) error {
+		return handlers.CloneEnvironment(c, ctx)
+	})))
+	s.router.PUT("/projects/:project_id/environments/:id", auth(editor(func(c echo.Context) error {
 		return handlers.UpdateEnvironment(c, ctx)
-	}))
-	s.router.DELETE("/projects/:project_id/environments/:id", auth(func(c echo.Context) error {
+	})))
+	s.router.DELETE("/projects/:project_id/environments/:id", auth(editor(func(c echo.Context) error {
 		return handlers.DeleteEnvironment(c, ctx)
-	}))
+	})))
+}
+
+func (s *Server) RegisterProjectVariableHandlers() {
+	auth := middleware.JWTAuthMiddleware(s.jwtSecret, s.dbService.GetQueries())
+	projectMember := middleware.RequireResourceAccess(middleware.RoleMetadata, middleware.ResourceParams{Project: "id"}, s.accessControl)
+	projectEditor := middleware.RequireResourceAccess(middleware.RoleEditor, middleware.ResourceParams{Project: "id"}, s.accessControl)
+	member := middleware.RequireResourceAccess(middleware.RoleMetadata, middleware.ResourceParams{Project: "id", ProjectVariable: "variable_id"}, s.accessControl)
+	viewer := middleware.RequireResourceAccess(middleware.RoleViewer, middleware.ResourceParams{Project: "id", ProjectVariable: "variable_id"}, s.accessControl)
+	editor := middleware.RequireResourceAccess(middleware.RoleEditor, middleware.ResourceParams{Project: "id", ProjectVariable: "variable_id"}, s.accessControl)
+	ctx := handlers.NewHandlerContext(s.dbService.GetQueries(), s.jwtSecret, s.accessControl, s.dbService)
+	s.router.POST("/projects/:id/variables", auth(projectEditor(func(c echo.Context) error {
+		return handlers.CreateProjectVariable(c, ctx)
+	})))
+	s.router.GET("/projects/:id/variables", auth(projectMember(func(c echo.Context) error {
+		return handlers.ListProjectVariables(c, ctx)
+	})))
+	s.router.GET("/projects/:id/variables/:variable_id", auth(member(func(c echo.Context) error {
+		return handlers.GetProjectVariable(c, ctx)
+	})))
+	s.router.POST("/projects/:id/variables/:variable_id/reveal", auth(viewer(func(c echo.Context) error {
+		return handlers.RevealProjectVariable(c, ctx)
+	})))
+	s.router.PUT("/projects/:id/variables/:variable_id", auth(editor(func(c echo.Context) error {
+		return handlers.UpdateProjectVariable(c, ctx)
+	})))
+	s.router.DELETE("/projects/:id/variables/:variable_id", auth(editor(func(c echo.Context) error {
+		return handlers.DeleteProjectVariable(c, ctx)
+	})))
+}
+
+func (s *Server) RegisterProjectSchemaHandlers() {
+	auth := middleware.JWTAuthMiddleware(s.jwtSecret, s.dbService.GetQueries())
+	member := middleware.RequireResourceAccess(middleware.RoleMetadata, middleware.ResourceParams{Project: "id"}, s.accessControl)
+	viewer := middleware.RequireResourceAccess(middleware.RoleViewer, middleware.ResourceParams{Project: "id"}, s.accessControl)
+	editor := middleware.RequireResourceAccess(middleware.RoleEditor, middleware.ResourceParams{Project: "id"}, s.accessControl)
+	ctx := handlers.NewHandlerContext(s.dbService.GetQueries(), s.jwtSecret, s.accessControl, s.dbService)
+	s.router.GET("/projects/:id/schema", auth(member(func(c echo.Context) error {
+		return handlers.GetProjectSchema(c, ctx)
+	})))
+	s.router.PUT("/projects/:id/schema", auth(editor(func(c echo.Context) error {
+		return handlers.UpdateProjectSchema(c, ctx)
+	})))
+	s.router.GET("/projects/:id/schema/validate", auth(viewer(func(c echo.Context) error {
+		return handlers.ValidateProjectSchema(c, ctx)
+	})))
 }
 
 func (s *Server) RegisterEnvironmentVariableHandlers() {
-	auth := middleware.JWTAuthMiddleware(s.jwtSecret)
-	ctx := handlers.NewHandlerContext(s.dbService.GetQueries(), s.jwtSecret, s.accessControl)
-	s.router.POST("/projects/:project_id/environments/:environment_id/variables", auth(func(c echo.Context) error {
+	auth := middleware.JWTAuthMiddleware(s.jwtSecret, s.dbService.GetQueries())
+	environmentMember := middleware.RequireResourceAccess(middleware.RoleMetadata, middleware.ResourceParams{Project: "project_id", Environment: "environment_id"}, s.accessControl)
+	environmentEditor := middleware.RequireResourceAccess(middleware.RoleEditor, middleware.ResourceParams{Project: "project_id", Environment: "environment_id"}, s.accessControl)
+	member := middleware.RequireResourceAccess(middleware.RoleMetadata, middleware.ResourceParams{Project: "project_id", Environment: "environment_id", Variable: "id"}, s.accessControl)
+	viewer := middleware.RequireResourceAccess(middleware.RoleViewer, middleware.ResourceParams{Project: "project_id", Environment: "environment_id", Variable: "id"}, s.accessControl)
+	editor := middleware.RequireResourceAccess(middleware.RoleEditor, middleware.ResourceParams{Project: "project_id", Environment: "environment_id", Variable: "id"}, s.accessControl)
+	ctx := handlers.NewHandlerContext(s.dbService.GetQueries(), s.jwtSecret, s.accessControl, s.dbService)
+	s.router.POST("/projects/:project_id/environments/:environment_id/variables", auth(environmentEditor(func(c echo.Context) error {
 		return handlers.CreateEnvironmentVariable(c, ctx)
-	}))
-	s.router.GET("/projects/:project_id/environments/:environment_id/variables/:id", auth(func(c echo.Context) error {
+	})))
+	s.router.GET("/projects/:project_id/environments/:environment_id/variables/:id", auth(member(func(c echo.Context) error {
 		return handlers.GetEnvironmentVariable(c, ctx)
-	}))
-	s.router.GET("/projects/:project_id/environments/:environment_id/variables", auth(func(c echo.Context) error {
+	})))
+	s.router.POST("/projects/:project_id/environments/:environment_id/variables/:id/reveal", auth(viewer(func(c echo.Context) error {
+		return handlers.RevealEnvironmentVariable(c, ctx)
+	})))
+	s.router.GET("/projects/:project_id/environments/:environment_id/variables", auth(environmentMember(func(c echo.Context) error {
 		return handlers.ListEnvironmentVariables(c, ctx)
-	}))
-	s.router.PUT("/projects/:project_id/environments/:environment_id/variables/:id", auth(func(c echo.Context) error {
+	})))
+	s.router.PUT("/projects/:project_id/environments/:environment_id/variables/:id", auth(editor(func(c echo.Context) error {
 		return handlers.UpdateEnvironmentVariable(c, ctx)
-	}))
-	s.router.DELETE("/projects/:project_id/environments/:environment_id/variables/:id", auth(func(c echo.Context) error {
+	})))
+	s.router.POST("/projects/:project_id/environments/:environment_id/variables\\:batch", auth(environmentEditor(func(c echo.Context) error {
+		return handlers.BatchEnvironmentVariables(c, ctx)
+	})))
+	s.router.PUT("/projects/:project_id/environments/:environment_id/variables", auth(environmentEditor(func(c echo.Context) error {
+		return handlers.ReplaceEnvironmentVariables(c, ctx)
+	})))
+	s.router.PUT("/projects/:project_id/environments/:environment_id/variables/by-key/:key", auth(environmentEditor(func(c echo.Context) error {
+		return handlers.UpsertEnvironmentVariable(c, ctx)
+	})))
+	s.router.DELETE("/projects/:project_id/environments/:environment_id/variables/:id", auth(editor(func(c echo.Context) error {
 		return handlers.DeleteEnvironmentVariable(c, ctx)
+	})))
+	s.router.PUT("/projects/:project_id/environments/:environment_id/variables/:id/rotation", auth(editor(func(c echo.Context) error {
+		return handlers.SetEnvironmentVariableRotation(c, ctx)
+	})))
+	s.router.PUT("/projects/:project_id/environments/:environment_id/variables/:id/labels", auth(editor(func(c echo.Context) error {
+		return handlers.SetEnvironmentVariableLabels(c, ctx)
+	})))
+	s.router.GET("/variables/stale", auth(func(c echo.Context) error {
+		return handlers.ListStaleVariables(c, ctx)
+	}))
+	s.router.GET("/variables/search", auth(func(c echo.Context) error {
+		return handlers.SearchVariables(c, ctx)
+	}))
+	s.router.POST("/variables/replace-value", auth(func(c echo.Context) error {
+		return handlers.ReplaceValue(c, ctx)
 	}))
 }
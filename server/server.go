@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"log"
 	"os/signal"
+	"slices"
+	"strings"
 	"syscall"
 	"time"
 
@@ -19,6 +21,7 @@ import (
 type DBService interface {
 	GetDB() *sql.DB
 	GetQueries() queries.Querier
+	ExecTx(ctx context.Context, fn func(queries.Querier) error) error
 	Health() (*database.HealthStatus, error)
 	Close() error
 }
@@ -41,6 +44,9 @@ func New(addr string, env *utils.EnvVar) *Server {
 	if err != nil {
 		panic(err)
 	}
+	// Make exactly the configured users instance admins
+	syncAdmins(dbService.GetQueries(), env.ADMIN_EMAILS)
+
 	// Create an AccessControlService instance
 	accessControl := utils.NewAccessControlService(dbService.GetQueries())
 
@@ -60,6 +66,36 @@ func New(addr string, env *utils.EnvVar) *Server {
 	return server
 }
 
+// syncAdmins makes the accounts listed in emails instance admins and revokes
+// admin from every other account, so removing an address from ADMIN_EMAILS
+// takes effect on the next start.
+func syncAdmins(q queries.Querier, emails []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	users, err := q.ListUsers(ctx)
+	if err != nil {
+		log.Printf("failed to sync admins: %v", err)
+		return
+	}
+	for _, user := range users {
+		listed := slices.ContainsFunc(emails, func(email string) bool {
+			return strings.EqualFold(email, user.Email)
+		})
+		if user.IsAdmin == listed {
+			continue
+		}
+		err := q.SetUserAdminByEmail(ctx, queries.SetUserAdminByEmailParams{
+			IsAdmin:   listed,
+			UpdatedAt: time.Now(),
+			Email:     user.Email,
+		})
+		if err != nil {
+			log.Printf("failed to update admin status of %s: %v", user.Email, err)
+		}
+	}
+}
+
 func gracefulShutdown(e *echo.Echo, done chan bool) {
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
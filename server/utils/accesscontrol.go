@@ -4,70 +4,65 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	database "ytsruh.com/envoy/server/database/generated"
 	shared "ytsruh.com/envoy/shared"
 )
 
 type AccessControlService interface {
-	RequireOwner(ctx context.Context, projectID string, userID string) error
-	RequireEditor(ctx context.Context, projectID string, userID string) error
-	RequireViewer(ctx context.Context, projectID string, userID string) error
 	GetRole(ctx context.Context, projectID string, userID string) (string, error)
+	ResolveResources(ctx context.Context, scope ResourceScope, userID string, minRole string) (*ResolvedResources, error)
 }
 
-type AccessControlServiceImpl struct {
-	queries database.Querier
+// Project roles, ordered from least to most privileged by roleRank.
+// RoleMetadata members can see which variables exist but not their values.
+const (
+	RoleMetadata = "metadata"
+	RoleViewer   = "viewer"
+	RoleEditor   = "editor"
+	RoleOwner    = "owner"
+)
+
+var roleRank = map[string]int{
+	RoleMetadata: 1,
+	RoleViewer:   2,
+	RoleEditor:   3,
+	RoleOwner:    4,
 }
 
-func NewAccessControlService(queries database.Querier) AccessControlService {
-	return &AccessControlServiceImpl{
-		queries: queries,
-	}
+// RoleAtLeast reports whether role grants at least the privileges of minRole.
+func RoleAtLeast(role, minRole string) bool {
+	return roleRank[role] >= roleRank[minRole] && roleRank[role] > 0
 }
 
-func (s *AccessControlServiceImpl) RequireOwner(ctx context.Context, projectID string, userID string) error {
-	count, err := s.queries.IsProjectOwner(ctx, database.IsProjectOwnerParams{
-		ID:      projectID,
-		OwnerID: userID,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to check ownership: %w", err)
-	}
-	if count == 0 {
-		return shared.ErrAccessDenied
-	}
-	return nil
+// ResourceScope identifies the project -> environment -> variable chain a
+// request operates on, or a project-level variable. Empty IDs are not resolved.
+type ResourceScope struct {
+	ProjectID         string
+	EnvironmentID     string
+	VariableID        string
+	ProjectVariableID string
 }
 
-func (s *AccessControlServiceImpl) RequireEditor(ctx context.Context, projectID string, userID string) error {
-	count, err := s.queries.CanUserModifyProject(ctx, database.CanUserModifyProjectParams{
-		ID:      projectID,
-		OwnerID: userID,
-		UserID:  userID,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to check editor permissions: %w", err)
-	}
-	if count == 0 {
-		return shared.ErrAccessDenied
-	}
-	return nil
+// ResolvedResources holds the resources loaded for a ResourceScope together
+// with the caller's effective role on the project.
+type ResolvedResources struct {
+	Project         database.Project
+	Environment     *database.Environment
+	Variable        *database.EnvironmentVariable
+	ProjectVariable *database.ProjectVariable
+	Role            string
 }
 
-func (s *AccessControlServiceImpl) RequireViewer(ctx context.Context, projectID string, userID string) error {
-	_, err := s.queries.GetAccessibleProject(ctx, database.GetAccessibleProjectParams{
-		ID:      projectID,
-		OwnerID: userID,
-		UserID:  userID,
-	})
-	if err == sql.ErrNoRows {
-		return shared.ErrAccessDenied
-	}
-	if err != nil {
-		return fmt.Errorf("failed to check viewer permissions: %w", err)
+type AccessControlServiceImpl struct {
+	queries database.Querier
+}
+
+func NewAccessControlService(queries database.Querier) AccessControlService {
+	return &AccessControlServiceImpl{
+		queries: queries,
 	}
-	return nil
 }
 
 func (s *AccessControlServiceImpl) GetRole(ctx context.Context, projectID string, userID string) (string, error) {
@@ -80,7 +75,7 @@ func (s *AccessControlServiceImpl) GetRole(ctx context.Context, projectID string
 		return "", fmt.Errorf("failed to check ownership: %w", err)
 	}
 	if ownerCount > 0 {
-		return "owner", nil
+		return RoleOwner, nil
 	}
 
 	// Check project membership
@@ -95,5 +90,79 @@ func (s *AccessControlServiceImpl) GetRole(ctx context.Context, projectID string
 		return "", fmt.Errorf("failed to get project membership: %w", err)
 	}
 
+	now := time.Now()
+	if membership.ExpiresAt.Valid && !membership.ExpiresAt.Time.After(now) {
+		return "", shared.ErrNotMember
+	}
+
+	// A temporarily elevated viewer acts as an editor until the elevation lapses
+	if membership.ElevatedUntil.Valid && membership.ElevatedUntil.Time.After(now) {
+		return RoleEditor, nil
+	}
+
 	return membership.Role, nil
 }
+
+// ResolveResources loads every resource in scope, verifying that each path
+// segment belongs to its parent and that the user holds at least minRole on
+// the project. Missing or mismatched resources return shared.ErrNotFound so
+// that IDs from other projects cannot be probed.
+func (s *AccessControlServiceImpl) ResolveResources(ctx context.Context, scope ResourceScope, userID string, minRole string) (*ResolvedResources, error) {
+	project, err := s.queries.GetProject(ctx, scope.ProjectID)
+	if err == sql.ErrNoRows {
+		return nil, shared.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch project: %w", err)
+	}
+
+	role, err := s.GetRole(ctx, project.ID, userID)
+	if err == shared.ErrNotMember {
+		return nil, shared.ErrAccessDenied
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !RoleAtLeast(role, minRole) {
+		return nil, shared.ErrAccessDenied
+	}
+
+	resources := &ResolvedResources{Project: project, Role: role}
+
+	if scope.ProjectVariableID != "" {
+		variable, err := s.queries.GetProjectVariable(ctx, scope.ProjectVariableID)
+		if err == sql.ErrNoRows || (err == nil && variable.ProjectID != project.ID) {
+			return nil, shared.ErrNotFound
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch project variable: %w", err)
+		}
+		resources.ProjectVariable = &variable
+	}
+
+	if scope.EnvironmentID == "" {
+		return resources, nil
+	}
+	environment, err := s.queries.GetEnvironment(ctx, scope.EnvironmentID)
+	if err == sql.ErrNoRows || (err == nil && environment.ProjectID != project.ID) {
+		return nil, shared.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch environment: %w", err)
+	}
+	resources.Environment = &environment
+
+	if scope.VariableID == "" {
+		return resources, nil
+	}
+	variable, err := s.queries.GetEnvironmentVariable(ctx, scope.VariableID)
+	if err == sql.ErrNoRows || (err == nil && variable.EnvironmentID != environment.ID) {
+		return nil, shared.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch environment variable: %w", err)
+	}
+	resources.Variable = &variable
+
+	return resources, nil
+}
@@ -0,0 +1,213 @@
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	database "ytsruh.com/envoy/server/database/generated"
+	shared "ytsruh.com/envoy/shared"
+)
+
+// fakeQuerier serves the lookups used by AccessControlService from memory.
+// Methods it does not implement panic through the nil embedded interface.
+type fakeQuerier struct {
+	database.Querier
+	projects         map[string]database.Project
+	environments     map[string]database.Environment
+	variables        map[string]database.EnvironmentVariable
+	projectVariables map[string]database.ProjectVariable
+	members          []database.ProjectUser
+}
+
+func (f *fakeQuerier) GetProject(ctx context.Context, id string) (database.Project, error) {
+	project, ok := f.projects[id]
+	if !ok {
+		return database.Project{}, sql.ErrNoRows
+	}
+	return project, nil
+}
+
+func (f *fakeQuerier) IsProjectOwner(ctx context.Context, arg database.IsProjectOwnerParams) (int64, error) {
+	if project, ok := f.projects[arg.ID]; ok && project.OwnerID == arg.OwnerID {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func (f *fakeQuerier) GetProjectMembership(ctx context.Context, arg database.GetProjectMembershipParams) (database.ProjectUser, error) {
+	for _, member := range f.members {
+		if member.ProjectID == arg.ProjectID && member.UserID == arg.UserID {
+			return member, nil
+		}
+	}
+	return database.ProjectUser{}, sql.ErrNoRows
+}
+
+func (f *fakeQuerier) GetEnvironment(ctx context.Context, id string) (database.Environment, error) {
+	environment, ok := f.environments[id]
+	if !ok {
+		return database.Environment{}, sql.ErrNoRows
+	}
+	return environment, nil
+}
+
+func (f *fakeQuerier) GetEnvironmentVariable(ctx context.Context, id string) (database.EnvironmentVariable, error) {
+	variable, ok := f.variables[id]
+	if !ok {
+		return database.EnvironmentVariable{}, sql.ErrNoRows
+	}
+	return variable, nil
+}
+
+func (f *fakeQuerier) GetProjectVariable(ctx context.Context, id string) (database.ProjectVariable, error) {
+	variable, ok := f.projectVariables[id]
+	if !ok {
+		return database.ProjectVariable{}, sql.ErrNoRows
+	}
+	return variable, nil
+}
+
+// newFakeQuerier returns two projects, "billing" owned by "owner" and "search"
+// owned by "other", each with environments and variables, plus members of
+// billing with various roles.
+func newFakeQuerier() *fakeQuerier {
+	now := time.Now()
+	return &fakeQuerier{
+		projects: map[string]database.Project{
+			"billing": {ID: "billing", OwnerID: "owner"},
+			"search":  {ID: "search", OwnerID: "other"},
+		},
+		environments: map[string]database.Environment{
+			"billing-prod":    {ID: "billing-prod", ProjectID: "billing"},
+			"billing-staging": {ID: "billing-staging", ProjectID: "billing"},
+			"search-prod":     {ID: "search-prod", ProjectID: "search"},
+		},
+		variables: map[string]database.EnvironmentVariable{
+			"billing-prod-key":    {ID: "billing-prod-key", EnvironmentID: "billing-prod"},
+			"billing-staging-key": {ID: "billing-staging-key", EnvironmentID: "billing-staging"},
+			"search-prod-key":     {ID: "search-prod-key", EnvironmentID: "search-prod"},
+		},
+		projectVariables: map[string]database.ProjectVariable{
+			"billing-shared": {ID: "billing-shared", ProjectID: "billing"},
+			"search-shared":  {ID: "search-shared", ProjectID: "search"},
+		},
+		members: []database.ProjectUser{
+			{ProjectID: "billing", UserID: "viewer", Role: RoleViewer},
+			{ProjectID: "billing", UserID: "contractor", Role: RoleEditor, ExpiresAt: sql.NullTime{Time: now.Add(-time.Hour), Valid: true}},
+			{ProjectID: "billing", UserID: "oncall", Role: RoleViewer, ElevatedUntil: sql.NullTime{Time: now.Add(time.Hour), Valid: true}},
+		},
+	}
+}
+
+func TestResolveResources(t *testing.T) {
+	tests := []struct {
+		name    string
+		scope   ResourceScope
+		userID  string
+		minRole string
+		wantErr error
+		role    string
+	}{
+		{
+			name:    "owner resolves full chain",
+			scope:   ResourceScope{ProjectID: "billing", EnvironmentID: "billing-prod", VariableID: "billing-prod-key"},
+			userID:  "owner",
+			minRole: RoleEditor,
+			role:    RoleOwner,
+		},
+		{
+			name:    "missing project",
+			scope:   ResourceScope{ProjectID: "missing"},
+			userID:  "owner",
+			minRole: RoleViewer,
+			wantErr: shared.ErrNotFound,
+		},
+		{
+			name:    "environment from another project",
+			scope:   ResourceScope{ProjectID: "billing", EnvironmentID: "search-prod"},
+			userID:  "owner",
+			minRole: RoleViewer,
+			wantErr: shared.ErrNotFound,
+		},
+		{
+			name:    "variable from another environment",
+			scope:   ResourceScope{ProjectID: "billing", EnvironmentID: "billing-prod", VariableID: "billing-staging-key"},
+			userID:  "owner",
+			minRole: RoleViewer,
+			wantErr: shared.ErrNotFound,
+		},
+		{
+			name:    "variable from another project",
+			scope:   ResourceScope{ProjectID: "billing", EnvironmentID: "billing-prod", VariableID: "search-prod-key"},
+			userID:  "owner",
+			minRole: RoleViewer,
+			wantErr: shared.ErrNotFound,
+		},
+		{
+			name:    "project variable from another project",
+			scope:   ResourceScope{ProjectID: "billing", ProjectVariableID: "search-shared"},
+			userID:  "owner",
+			minRole: RoleViewer,
+			wantErr: shared.ErrNotFound,
+		},
+		{
+			name:    "viewer meets viewer minimum",
+			scope:   ResourceScope{ProjectID: "billing", EnvironmentID: "billing-prod"},
+			userID:  "viewer",
+			minRole: RoleViewer,
+			role:    RoleViewer,
+		},
+		{
+			name:    "viewer below editor minimum",
+			scope:   ResourceScope{ProjectID: "billing", EnvironmentID: "billing-prod"},
+			userID:  "viewer",
+			minRole: RoleEditor,
+			wantErr: shared.ErrAccessDenied,
+		},
+		{
+			name:    "elevated viewer acts as editor",
+			scope:   ResourceScope{ProjectID: "billing", EnvironmentID: "billing-prod"},
+			userID:  "oncall",
+			minRole: RoleEditor,
+			role:    RoleEditor,
+		},
+		{
+			name:    "expired membership",
+			scope:   ResourceScope{ProjectID: "billing"},
+			userID:  "contractor",
+			minRole: RoleMetadata,
+			wantErr: shared.ErrAccessDenied,
+		},
+		{
+			name:    "not a member",
+			scope:   ResourceScope{ProjectID: "billing"},
+			userID:  "stranger",
+			minRole: RoleMetadata,
+			wantErr: shared.ErrAccessDenied,
+		},
+	}
+
+	service := NewAccessControlService(newFakeQuerier())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resources, err := service.ResolveResources(context.Background(), tt.scope, tt.userID, tt.minRole)
+			if err != tt.wantErr {
+				t.Fatalf("ResolveResources() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if resources.Role != tt.role {
+				t.Errorf("Role = %q, want %q", resources.Role, tt.role)
+			}
+			if tt.scope.EnvironmentID != "" && (resources.Environment == nil || resources.Environment.ID != tt.scope.EnvironmentID) {
+				t.Errorf("Environment = %v, want %q", resources.Environment, tt.scope.EnvironmentID)
+			}
+			if tt.scope.VariableID != "" && (resources.Variable == nil || resources.Variable.ID != tt.scope.VariableID) {
+				t.Errorf("Variable = %v, want %q", resources.Variable, tt.scope.VariableID)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"reflect"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -17,6 +18,8 @@ type EnvVar struct {
 	JWT_SECRET string
 	DB_URL     string
 	DB_TOKEN   string
+	// ADMIN_EMAILS is optional; users with these emails are granted instance admin
+	ADMIN_EMAILS []string
 }
 
 // LoadAndValidateEnv loads environment variables from .env file (in development) or from system environment (in production) and validates that all required variables are set. Returns the loaded environment variables and an error if any required variable is missing
@@ -25,9 +28,10 @@ func LoadAndValidateEnv() (*EnvVar, error) {
 	_ = godotenv.Load()
 
 	env := EnvVar{
-		DB_URL:     os.Getenv("DB_URL"),
-		DB_TOKEN:   os.Getenv("DB_TOKEN"),
-		JWT_SECRET: os.Getenv("JWT_SECRET"),
+		DB_URL:       os.Getenv("DB_URL"),
+		DB_TOKEN:     os.Getenv("DB_TOKEN"),
+		JWT_SECRET:   os.Getenv("JWT_SECRET"),
+		ADMIN_EMAILS: splitList(os.Getenv("ADMIN_EMAILS")),
 	}
 
 	// Validate that all required environment variables are set
@@ -61,3 +65,27 @@ func ValidateEnvVars(env EnvVar) []string {
 func GetEnvVars() *EnvVar {
 	return Config
 }
+
+// IsAdminEmail reports whether email is listed in ADMIN_EMAILS
+func IsAdminEmail(email string) bool {
+	if Config == nil {
+		return false
+	}
+	for _, adminEmail := range Config.ADMIN_EMAILS {
+		if strings.EqualFold(adminEmail, email) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitList splits a comma separated value into its trimmed, non-empty parts
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
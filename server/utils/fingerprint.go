@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// ValueFingerprint returns the hex encoded SHA-256 of a stored value, used to
+// find a value without revealing it. File variables are hashed on their
+// decoded contents so that a fingerprint of the file on disk matches.
+func ValueFingerprint(value, variableType string) string {
+	data := []byte(value)
+	if variableType == VariableTypeFile {
+		if decoded, err := base64.StdEncoding.DecodeString(value); err == nil {
+			data = decoded
+		}
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// IsFingerprint reports whether s is a hex encoded SHA-256 hash.
+func IsFingerprint(s string) bool {
+	if len(s) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
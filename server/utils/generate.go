@@ -0,0 +1,242 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Generator kinds accepted by GenerateValue.
+const (
+	GeneratorHex          = "hex"
+	GeneratorBase64       = "base64"
+	GeneratorAlphanumeric = "alphanumeric"
+	GeneratorUUID         = "uuid"
+	GeneratorPassword     = "password"
+	GeneratorRSA          = "rsa"
+	GeneratorEd25519      = "ed25519"
+)
+
+const maxGeneratedLength = 1024
+
+// Password character classes. symbolChars leaves out $, { and } so that a
+// generated value can never be read as a ${...} variable reference.
+const (
+	lowerChars  = "abcdefghijklmnopqrstuvwxyz"
+	upperChars  = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digitChars  = "0123456789"
+	symbolChars = "!#%&()*+,-./:;<=>?@[]^_|~"
+)
+
+// passwordClasses maps the class letters of a password spec to their characters.
+var passwordClasses = map[rune]string{
+	'l': lowerChars,
+	'u': upperChars,
+	'd': digitChars,
+	's': symbolChars,
+}
+
+// GenerateValue produces a random value from a generator spec of the form
+// kind[:length[:options]]:
+//
+//	hex:32           32 random bytes, hex encoded
+//	base64:32        32 random bytes, standard base64 encoded
+//	alphanumeric:40  40 random letters and digits
+//	uuid             a random UUID
+//	password:24:luds 24 characters from the lower, upper, digit and symbol
+//	                 classes, with at least one of each (default luds)
+//	rsa:4096         an RSA private and public key in PEM (default 2048 bits)
+//	ed25519          an Ed25519 private and public key in PEM
+func GenerateValue(spec string) (string, error) {
+	generate, err := parseGeneratorSpec(spec)
+	if err != nil {
+		return "", err
+	}
+	return generate()
+}
+
+// ValidateGeneratorSpec checks a generator spec without generating a value.
+func ValidateGeneratorSpec(spec string) error {
+	_, err := parseGeneratorSpec(spec)
+	return err
+}
+
+func parseGeneratorSpec(spec string) (func() (string, error), error) {
+	parts := strings.Split(spec, ":")
+	kind := parts[0]
+	args := parts[1:]
+
+	switch kind {
+	case GeneratorHex, GeneratorBase64, GeneratorAlphanumeric:
+		if len(args) != 1 {
+			return nil, fmt.Errorf("%s generator needs a length, e.g. %s:32", kind, kind)
+		}
+		n, err := generatedLength(args[0])
+		if err != nil {
+			return nil, err
+		}
+		switch kind {
+		case GeneratorHex:
+			return func() (string, error) {
+				b, err := randomBytes(n)
+				return hex.EncodeToString(b), err
+			}, nil
+		case GeneratorBase64:
+			return func() (string, error) {
+				b, err := randomBytes(n)
+				return base64.StdEncoding.EncodeToString(b), err
+			}, nil
+		default:
+			return func() (string, error) {
+				return randomString(n, lowerChars+upperChars+digitChars)
+			}, nil
+		}
+	case GeneratorUUID:
+		if len(args) != 0 {
+			return nil, fmt.Errorf("uuid generator takes no options")
+		}
+		return func() (string, error) { return GenerateUUID(), nil }, nil
+	case GeneratorPassword:
+		return parsePasswordSpec(args)
+	case GeneratorRSA:
+		bits := 2048
+		if len(args) > 1 {
+			return nil, fmt.Errorf("rsa generator takes only a key size")
+		}
+		if len(args) == 1 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || (n != 2048 && n != 3072 && n != 4096) {
+				return nil, fmt.Errorf("rsa key size must be 2048, 3072 or 4096")
+			}
+			bits = n
+		}
+		return func() (string, error) {
+			key, err := rsa.GenerateKey(rand.Reader, bits)
+			if err != nil {
+				return "", fmt.Errorf("failed to generate rsa key: %w", err)
+			}
+			return encodeKeyPair(key, &key.PublicKey)
+		}, nil
+	case GeneratorEd25519:
+		if len(args) != 0 {
+			return nil, fmt.Errorf("ed25519 generator takes no options")
+		}
+		return func() (string, error) {
+			public, private, err := ed25519.GenerateKey(rand.Reader)
+			if err != nil {
+				return "", fmt.Errorf("failed to generate ed25519 key: %w", err)
+			}
+			return encodeKeyPair(private, public)
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown generator %q, expected one of hex, base64, alphanumeric, uuid, password, rsa or ed25519", kind)
+	}
+}
+
+func generatedLength(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 || n > maxGeneratedLength {
+		return 0, fmt.Errorf("length must be between 1 and %d", maxGeneratedLength)
+	}
+	return n, nil
+}
+
+func parsePasswordSpec(args []string) (func() (string, error), error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, fmt.Errorf("password generator needs a length and optional classes, e.g. password:24:luds")
+	}
+	n, err := generatedLength(args[0])
+	if err != nil {
+		return nil, err
+	}
+	classes := "luds"
+	if len(args) == 2 {
+		classes = args[1]
+	}
+
+	var charset string
+	var required []string
+	for _, c := range classes {
+		chars, ok := passwordClasses[c]
+		if !ok {
+			return nil, fmt.Errorf("unknown password class %q, use l, u, d or s", c)
+		}
+		if strings.Contains(charset, chars) {
+			continue
+		}
+		charset += chars
+		required = append(required, chars)
+	}
+	if len(required) == 0 {
+		return nil, fmt.Errorf("password needs at least one character class")
+	}
+	if n < len(required) {
+		return nil, fmt.Errorf("password length must be at least %d for the requested classes", len(required))
+	}
+
+	return func() (string, error) {
+		for {
+			password, err := randomString(n, charset)
+			if err != nil {
+				return "", err
+			}
+			if containsEveryClass(password, required) {
+				return password, nil
+			}
+		}
+	}, nil
+}
+
+func containsEveryClass(s string, classes []string) bool {
+	for _, chars := range classes {
+		if !strings.ContainsAny(s, chars) {
+			return false
+		}
+	}
+	return true
+}
+
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to read random bytes: %w", err)
+	}
+	return b, nil
+}
+
+// randomString returns n characters drawn uniformly from charset.
+func randomString(n int, charset string) (string, error) {
+	max := big.NewInt(int64(len(charset)))
+	b := make([]byte, n)
+	for i := range b {
+		idx, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("failed to read random bytes: %w", err)
+		}
+		b[i] = charset[idx.Int64()]
+	}
+	return string(b), nil
+}
+
+// encodeKeyPair returns the PKCS#8 private key followed by the PKIX public key,
+// both PEM encoded.
+func encodeKeyPair(private, public any) (string, error) {
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode private key: %w", err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode public key: %w", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER})) +
+		string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})), nil
+}
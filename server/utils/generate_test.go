@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestGenerateValueHasNoReferences checks that generated values are never
+// expanded as ${...} references when revealed or exported.
+func TestGenerateValueHasNoReferences(t *testing.T) {
+	if strings.ContainsAny(symbolChars, "${}") {
+		t.Fatalf("symbolChars %q contains reference syntax", symbolChars)
+	}
+
+	specs := []string{"password:64:s", "password:64", "base64:64", "hex:32", "alphanumeric:64", "uuid", "ed25519"}
+	for _, spec := range specs {
+		for i := 0; i < 200; i++ {
+			value, err := GenerateValue(spec)
+			if err != nil {
+				t.Fatalf("GenerateValue(%q) error = %v", spec, err)
+			}
+			if strings.Contains(value, "${") {
+				t.Fatalf("GenerateValue(%q) = %q contains a reference", spec, value)
+			}
+		}
+	}
+}
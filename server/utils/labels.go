@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MaxLabels caps how many labels a single variable may carry.
+const MaxLabels = 20
+
+const maxLabelLength = 63
+
+// IsLabelName reports whether s is a valid label name: 1-63 letters, digits,
+// hyphens, underscores, dots or slashes.
+func IsLabelName(s string) bool {
+	if s == "" || len(s) > maxLabelLength {
+		return false
+	}
+	for _, r := range s {
+		if !((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') ||
+			r == '-' || r == '_' || r == '.' || r == '/') {
+			return false
+		}
+	}
+	return true
+}
+
+// IsLabelValue reports whether s is a valid label value. Values may be empty
+// but cannot contain commas, equals signs or whitespace.
+func IsLabelValue(s string) bool {
+	return len(s) <= maxLabelLength && !strings.ContainsAny(s, ",= \t\r\n")
+}
+
+// ValidateLabels checks the names and values of a set of labels.
+func ValidateLabels(labels map[string]string) error {
+	if len(labels) > MaxLabels {
+		return fmt.Errorf("at most %d labels are allowed", MaxLabels)
+	}
+	for name, value := range labels {
+		if !IsLabelName(name) {
+			return fmt.Errorf("invalid label name %q", name)
+		}
+		if !IsLabelValue(value) {
+			return fmt.Errorf("invalid value for label %s", name)
+		}
+	}
+	return nil
+}
+
+// FormatLabels encodes labels for storage as "name=value" pairs sorted by name
+// and joined with commas.
+func FormatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for name, value := range labels {
+		pairs = append(pairs, name+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// ParseLabels decodes labels stored by FormatLabels. It returns nil when there
+// are none.
+func ParseLabels(s string) map[string]string {
+	if s == "" {
+		return nil
+	}
+	labels := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		name, value, _ := strings.Cut(pair, "=")
+		labels[name] = value
+	}
+	return labels
+}
+
+// MatchLabels reports whether labels satisfy every selector. A selector of the
+// form "name=value" requires that exact value; a bare "name" only requires the
+// label to be present.
+func MatchLabels(labels map[string]string, selectors []string) bool {
+	for _, selector := range selectors {
+		name, want, hasValue := strings.Cut(selector, "=")
+		value, ok := labels[name]
+		if !ok || (hasValue && value != want) {
+			return false
+		}
+	}
+	return true
+}
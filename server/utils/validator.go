@@ -18,6 +18,14 @@ func init() {
 	// Register custom validation functions
 	validate.RegisterValidation("project_name", validateName)
 	validate.RegisterValidation("environment_name", validateName)
+	validate.RegisterValidation("duration", validateDuration)
+	validate.RegisterValidation("variable_type", validateVariableType)
+	validate.RegisterValidation("typed_value", validateTypedValue)
+	validate.RegisterValidation("generator", validateGenerator)
+	validate.RegisterValidation("file_mode", validateFileMode)
+	validate.RegisterValidation("filename", validateFilename)
+	validate.RegisterValidation("labels", validateLabels)
+	validate.RegisterValidation("fingerprint", validateFingerprint)
 }
 
 // Validate validates a struct using the validator package
@@ -50,6 +58,64 @@ func validateName(fl validator.FieldLevel) bool {
 	return true
 }
 
+// validateDuration custom validation for durations such as "12h" or "7d"
+func validateDuration(fl validator.FieldLevel) bool {
+	_, err := ParseDuration(fl.Field().String())
+	return err == nil
+}
+
+// validateVariableType custom validation for variable types; empty means string
+func validateVariableType(fl validator.FieldLevel) bool {
+	t := fl.Field().String()
+	return t == "" || IsVariableType(t)
+}
+
+// validateTypedValue custom validation checking that a value matches the Type
+// and AllowedValues fields of the same struct. Empty values are left to required.
+func validateTypedValue(fl validator.FieldLevel) bool {
+	if fl.Field().String() == "" {
+		return true
+	}
+	parent := fl.Parent()
+	typeField := parent.FieldByName("Type")
+	if !typeField.IsValid() {
+		return true
+	}
+	var allowed []string
+	if allowedField := parent.FieldByName("AllowedValues"); allowedField.IsValid() {
+		if values, ok := allowedField.Interface().([]string); ok {
+			allowed = values
+		}
+	}
+	return ValidateVariableValue(typeField.String(), allowed, fl.Field().String()) == nil
+}
+
+// validateGenerator custom validation for secret generator specs such as "base64:32"
+func validateGenerator(fl validator.FieldLevel) bool {
+	return ValidateGeneratorSpec(fl.Field().String()) == nil
+}
+
+// validateFileMode custom validation for octal permission modes such as "0600"
+func validateFileMode(fl validator.FieldLevel) bool {
+	return IsFileMode(fl.Field().String())
+}
+
+// validateFilename custom validation for file names without directory parts
+func validateFilename(fl validator.FieldLevel) bool {
+	return IsFilename(fl.Field().String())
+}
+
+// validateLabels custom validation for label maps such as {"team": "payments"}
+func validateLabels(fl validator.FieldLevel) bool {
+	labels, ok := fl.Field().Interface().(map[string]string)
+	return ok && ValidateLabels(labels) == nil
+}
+
+// validateFingerprint custom validation for hex encoded SHA-256 value fingerprints
+func validateFingerprint(fl validator.FieldLevel) bool {
+	return IsFingerprint(fl.Field().String())
+}
+
 // formatValidationError converts validation errors to user-friendly messages
 func formatValidationError(fe validator.FieldError) string {
 	field := fe.Field()
@@ -67,6 +133,26 @@ func formatValidationError(fe validator.FieldError) string {
 		return fmt.Sprintf("%s must be a valid email address", field)
 	case "project_name", "environment_name":
 		return fmt.Sprintf("%s must be 1-100 characters and contain only letters, numbers, spaces, hyphens, and underscores", field)
+	case "duration":
+		return fmt.Sprintf("%s must be a positive duration such as 12h or 7d", field)
+	case "variable_type":
+		return fmt.Sprintf("%s must be one of %s", field, strings.Join(VariableTypes, ", "))
+	case "typed_value":
+		return fmt.Sprintf("%s is not valid for the variable type", field)
+	case "generator":
+		return fmt.Sprintf("%s must be a generator such as hex:32, base64:32, alphanumeric:40, uuid, password:24, rsa:2048 or ed25519", field)
+	case "file_mode":
+		return fmt.Sprintf("%s must be an octal permission mode such as 0600", field)
+	case "filename":
+		return fmt.Sprintf("%s must be a file name without directories", field)
+	case "labels":
+		return fmt.Sprintf("%s must have at most %d names of letters, digits, '-', '_', '.' or '/' with values free of commas, '=' and spaces", field, MaxLabels)
+	case "fingerprint":
+		return fmt.Sprintf("%s must be a hex encoded SHA-256 hash", field)
+	case "ne":
+		return fmt.Sprintf("%s cannot be %s", field, param)
+	case "required_without":
+		return fmt.Sprintf("%s is required unless %s is set", field, param)
 	case "env_var_value":
 		return fmt.Sprintf("%s must be at most 255 characters", field)
 	default:
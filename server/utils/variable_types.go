@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Variable types. Values of every type are stored as strings; the type only
+// controls validation and how clients present the value.
+const (
+	VariableTypeString   = "string"
+	VariableTypeSecret   = "secret"
+	VariableTypeNumber   = "number"
+	VariableTypeBoolean  = "boolean"
+	VariableTypeURL      = "url"
+	VariableTypeJSON     = "json"
+	VariableTypeEnum     = "enum"
+	VariableTypeDuration = "duration"
+	VariableTypeFile     = "file"
+)
+
+// File variables hold the base64 encoded contents of a file, along with the
+// filename and permissions it is written with.
+const (
+	MaxFileSize     = 1 << 20
+	DefaultFileMode = "0600"
+)
+
+// VariableTypes lists every supported variable type.
+var VariableTypes = []string{
+	VariableTypeString,
+	VariableTypeSecret,
+	VariableTypeNumber,
+	VariableTypeBoolean,
+	VariableTypeURL,
+	VariableTypeJSON,
+	VariableTypeEnum,
+	VariableTypeDuration,
+	VariableTypeFile,
+}
+
+// IsVariableType reports whether t is a supported variable type.
+func IsVariableType(t string) bool {
+	return slices.Contains(VariableTypes, t)
+}
+
+// IsFileMode reports whether s is an octal permission mode such as 0600 or 644.
+func IsFileMode(s string) bool {
+	s = strings.TrimPrefix(s, "0")
+	if len(s) != 3 {
+		return false
+	}
+	_, err := strconv.ParseUint(s, 8, 32)
+	return err == nil
+}
+
+// IsFilename reports whether s is a plain file name with no directory parts.
+func IsFilename(s string) bool {
+	return s != "" && s != "." && s != ".." && len(s) <= 255 && !strings.ContainsAny(s, "/\\\x00")
+}
+
+// SplitAllowedValues parses the comma-separated allowed values of an enum.
+func SplitAllowedValues(s string) []string {
+	var values []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// ValidateVariableValue checks that value is valid for the variable type. An
+// empty type is treated as a string. allowed is only used by enums. Values that
+// contain ${...} references are not checked because their final value is only
+// known once expanded. Errors never include the value, so they can be shown to
+// users who may not read it.
+func ValidateVariableValue(variableType string, allowed []string, value string) error {
+	if strings.Contains(value, "${") && variableType != VariableTypeEnum {
+		return nil
+	}
+	switch variableType {
+	case "", VariableTypeString, VariableTypeSecret:
+		return nil
+	case VariableTypeNumber:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("value is not a number")
+		}
+	case VariableTypeBoolean:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("value is not a boolean, use true or false")
+		}
+	case VariableTypeURL:
+		u, err := url.Parse(value)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("value is not an absolute URL")
+		}
+	case VariableTypeJSON:
+		if !json.Valid([]byte(value)) {
+			return fmt.Errorf("value is not valid JSON")
+		}
+	case VariableTypeEnum:
+		if len(allowed) == 0 {
+			return fmt.Errorf("enum variables need at least one allowed value")
+		}
+		if !slices.Contains(allowed, value) {
+			return fmt.Errorf("value is not one of %s", strings.Join(allowed, ", "))
+		}
+	case VariableTypeFile:
+		data, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return fmt.Errorf("file contents must be base64 encoded")
+		}
+		if len(data) > MaxFileSize {
+			return fmt.Errorf("file is larger than %d bytes", MaxFileSize)
+		}
+	case VariableTypeDuration:
+		if _, err := time.ParseDuration(value); err != nil {
+			if _, err := ParseDuration(value); err != nil {
+				return fmt.Errorf("value is not a duration such as 30s, 5m or 7d")
+			}
+		}
+	default:
+		return fmt.Errorf("unknown variable type %q", variableType)
+	}
+	return nil
+}
@@ -86,3 +86,33 @@ type EnvironmentsResponse struct {
 type EnvironmentVariablesResponse struct {
 	Variables []EnvironmentVariableResponse `json:"variables"`
 }
+
+// AdminUserResponse represents a user account as seen by an instance admin.
+type AdminUserResponse struct {
+	UserID     UserID     `json:"user_id"`
+	Name       string     `json:"name"`
+	Email      string     `json:"email"`
+	IsAdmin    bool       `json:"is_admin"`
+	DisabledAt *Timestamp `json:"disabled_at"`
+	CreatedAt  Timestamp  `json:"created_at"`
+}
+
+// AdminProjectResponse represents a project and its owner as seen by an instance admin.
+type AdminProjectResponse struct {
+	ID            ProjectID `json:"id"`
+	Name          string    `json:"name"`
+	GitRepo       *string   `json:"git_repo"`
+	OwnerID       UserID    `json:"owner_id"`
+	OwnerEmail    string    `json:"owner_email"`
+	OwnerDisabled bool      `json:"owner_disabled"`
+	CreatedAt     Timestamp `json:"created_at"`
+}
+
+// InstanceStatsResponse contains instance-wide resource counts.
+type InstanceStatsResponse struct {
+	Users         int64 `json:"users"`
+	DisabledUsers int64 `json:"disabled_users"`
+	Projects      int64 `json:"projects"`
+	Environments  int64 `json:"environments"`
+	Variables     int64 `json:"variables"`
+}
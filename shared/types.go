@@ -14,6 +14,9 @@ type EnvironmentID string
 // EnvironmentVariableID is a unique identifier for an environment variable.
 type EnvironmentVariableID string
 
+// ProjectVariableID is a unique identifier for a project-level variable.
+type ProjectVariableID string
+
 // Role represents a user's permission level within a project.
 type Role string
 
@@ -24,6 +27,8 @@ const (
 	RoleEditor Role = "editor"
 	// RoleViewer can only read resources within the project
 	RoleViewer Role = "viewer"
+	// RoleMetadata can see which variables exist but not their values
+	RoleMetadata Role = "metadata"
 )
 
 // StringToProjectID converts a string to ProjectID.